@@ -22,8 +22,10 @@ func main() {
 
 	switch cmd {
 	case "gt0", "gt1", "gt2", "gt3", "create-default":
+		dryRun, args := commands.ParseDryRunFlag(args)
+		templateName, args := commands.ParseTemplateFlag(args)
 		hasBody, bodyValue, remainingArgs := commands.ParseBodyFlag(args)
-		commands.CreateIssue(remainingArgs, cmd, hasBody, bodyValue)
+		commands.CreateIssue(remainingArgs, cmd, hasBody, bodyValue, templateName, dryRun)
 	case "list", "":
 		commands.ListIssues(args)
 	case "p0", "p1", "p2", "p3":
@@ -39,8 +41,36 @@ func main() {
 		commands.CloseIssue(args)
 	case "rm", "delete":
 		commands.DeleteIssue(args)
+	case "prio":
+		commands.SetPriority(args)
+	case "label":
+		commands.LabelIssues(args)
+	case "hooks":
+		commands.HooksCommand(args)
+	case "pr":
+		commands.CreatePullRequest(args)
+	case "plan":
+		commands.PlanIssues(args)
+	case "drafts":
+		commands.DraftsCommand(args)
+	case "changelog":
+		commands.Changelog(args)
+	case "trash":
+		commands.TrashCommand(args)
+	case "undo":
+		commands.UndoLast(args)
 	case "setup":
-		commands.SetupRepo()
+		commands.SetupRepo(args)
+	case "sync":
+		commands.SyncTasks(args)
+	case "sync-md":
+		commands.SyncMarkdown(args)
+	case "todos":
+		commands.ScanTodos()
+	case "export":
+		commands.ExportIssues(args)
+	case "import":
+		commands.ImportIssues(args)
 	case "help", "--help", "-h":
 		commands.ShowHelp()
 	case "view":
@@ -74,7 +104,7 @@ func detectCommand() (string, []string) {
 
 	firstArg := os.Args[1]
 
-	knownCommands := []string{"list", "p0", "p1", "p2", "p3", "active", "start", "activate", "pause", "stop", "done", "rm", "delete", "setup", "help", "--help", "-h"}
+	knownCommands := []string{"list", "p0", "p1", "p2", "p3", "active", "start", "activate", "pause", "stop", "done", "rm", "delete", "prio", "label", "undo", "trash", "hooks", "pr", "plan", "drafts", "changelog", "view", "edit", "setup", "sync", "sync-md", "todos", "export", "import", "help", "--help", "-h"}
 	if slices.Contains(knownCommands, firstArg) {
 		return firstArg, os.Args[2:]
 	}
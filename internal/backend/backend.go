@@ -0,0 +1,113 @@
+// Package backend defines the task store gt commands operate on. GitHub Issues
+// is the default backend; alternative backends keep tasks elsewhere while
+// exposing the same issue model, so listing, colors and editing work unchanged.
+package backend
+
+import "github.com/DeprecatedLuar/ghtask/internal"
+
+// Backend kinds selectable through the "backend" setting
+const (
+	KindGitHub   = "github"
+	KindGit      = "git"
+	KindMarkdown = "markdown"
+)
+
+// List states accepted by Backend.List
+const (
+	ListOpen   = "open"
+	ListClosed = "closed"
+	ListAll    = "all"
+)
+
+// Backend is a store of issues. Numbers are the user-facing issue numbers.
+type Backend interface {
+	// List returns issues in the given state (ListOpen, ListClosed or ListAll)
+	List(state string) ([]internal.Issue, error)
+	// Get returns a single issue including its body and comments
+	Get(number int) (internal.Issue, error)
+	// Create creates an issue and returns it with its assigned number
+	Create(title, body string, labels []string) (internal.Issue, error)
+	// Update applies the non-nil fields of an update to an issue
+	Update(number int, update Update) error
+	Close(number int) error
+	Reopen(number int) error
+	Delete(number int) error
+	AddComment(number int, body string) error
+	// Labels returns the names of labels known to the backend
+	Labels() ([]string, error)
+	CreateLabel(name, color, description string) error
+}
+
+// Update describes a change to an issue. Nil/empty fields are left untouched.
+type Update struct {
+	Title           *string
+	Body            *string
+	AddLabels       []string
+	RemoveLabels    []string
+	AddAssignees    []string
+	RemoveAssignees []string
+}
+
+// IsEmpty reports whether the update changes nothing
+func (u Update) IsEmpty() bool {
+	return u.Title == nil && u.Body == nil &&
+		len(u.AddLabels) == 0 && len(u.RemoveLabels) == 0 &&
+		len(u.AddAssignees) == 0 && len(u.RemoveAssignees) == 0
+}
+
+// Syncer is implemented by backends that keep a local copy of the tasks and
+// need to exchange changes with collaborators explicitly.
+type Syncer interface {
+	Sync(remote string) (SyncResult, error)
+}
+
+// SyncResult summarizes what a Sync call changed locally
+type SyncResult struct {
+	Fetched int // tasks that only existed remotely
+	Updated int // local tasks fast-forwarded to the remote version
+	Merged  int // tasks changed on both sides and merged
+
+	// Local tasks that had not been pushed yet and got a new number because
+	// a task on the remote already had theirs
+	Renumbered []Renumbering
+}
+
+// Renumbering is a task number changed by a sync
+type Renumbering struct {
+	From, To int
+}
+
+// BodyLister is implemented by backends whose List leaves out issue bodies to
+// stay fast, for commands that need to search them.
+type BodyLister interface {
+	ListWithBodies(state string) ([]internal.Issue, error)
+}
+
+// PullRequester is implemented by backends hosted on a forge that can open
+// pull requests for a branch.
+type PullRequester interface {
+	CreatePullRequest(pr PullRequest) (string, error) // returns the PR URL
+}
+
+// PullRequest describes a pull request to open
+type PullRequest struct {
+	Title string
+	Body  string
+	Head  string // branch with the changes
+	Base  string // branch to merge into; empty for the repository default
+	Draft bool
+}
+
+// PullRequestLinker is implemented by backends that know which pull requests
+// reference an issue.
+type PullRequestLinker interface {
+	LinkedPullRequests(number int) ([]LinkedPullRequest, error)
+}
+
+// LinkedPullRequest is a pull request referencing an issue
+type LinkedPullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"` // OPEN, CLOSED or MERGED
+	URL    string `json:"url"`
+}
@@ -0,0 +1,211 @@
+package backend
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+// DryRun wraps a backend so that nothing is modified. Reads go to the wrapped
+// backend; every mutating call is written to out instead, together with the
+// state the issue would end up in:
+//
+//	would update #12: +active -inbox
+//	  labels: inbox, P2 → P2, active
+//
+// The wrapped backend may be nil to plan commands without any backend; reads
+// then see no issues and no labels. Issues it would create have number 0.
+type DryRun struct {
+	inner   Backend
+	out     io.Writer
+	planned internal.Issue // last issue it would have created
+	mu      sync.Mutex
+}
+
+// NewDryRun returns a backend printing the calls it would make on inner to out
+func NewDryRun(inner Backend, out io.Writer) *DryRun {
+	return &DryRun{inner: inner, out: out}
+}
+
+func (d *DryRun) List(state string) ([]internal.Issue, error) {
+	if d.inner == nil {
+		return nil, nil
+	}
+	return d.inner.List(state)
+}
+
+func (d *DryRun) Get(number int) (internal.Issue, error) {
+	if number == 0 {
+		return d.planned, nil
+	}
+	if d.inner == nil {
+		return internal.Issue{Number: number, State: internal.StateOpen}, nil
+	}
+	return d.inner.Get(number)
+}
+
+func (d *DryRun) Create(title, body string, labels []string) (internal.Issue, error) {
+	issue := internal.Issue{
+		Title:  title,
+		Body:   body,
+		State:  internal.StateOpen,
+		Labels: applyLabels(nil, labels, nil),
+	}
+
+	d.print(fmt.Sprintf("would create issue %q", title),
+		"labels: "+formatLabels(issue.Labels),
+		fmt.Sprintf("body lines: %d", countLines(body)))
+
+	d.mu.Lock()
+	d.planned = issue
+	d.mu.Unlock()
+	return issue, nil
+}
+
+func (d *DryRun) Update(number int, update Update) error {
+	issue, err := d.Get(number)
+	if err != nil {
+		return err
+	}
+
+	var changes, details []string
+	if update.Title != nil {
+		changes = append(changes, "title")
+		details = append(details, fmt.Sprintf("title: %q → %q", issue.Title, *update.Title))
+	}
+	if update.Body != nil {
+		changes = append(changes, "body")
+		details = append(details, fmt.Sprintf("body lines: %d → %d", countLines(issue.Body), countLines(*update.Body)))
+	}
+	if len(update.AddLabels) > 0 || len(update.RemoveLabels) > 0 {
+		changes = append(changes, formatChanges(update.AddLabels, update.RemoveLabels))
+		details = append(details, fmt.Sprintf("labels: %s → %s", formatLabels(issue.Labels),
+			formatLabels(applyLabels(issue.Labels, update.AddLabels, update.RemoveLabels))))
+	}
+	if len(update.AddAssignees) > 0 || len(update.RemoveAssignees) > 0 {
+		changes = append(changes, "assignees "+formatChanges(update.AddAssignees, update.RemoveAssignees))
+		details = append(details, fmt.Sprintf("assignees: %s → %s", formatUsers(issue.Assignees),
+			formatUsers(applyAssignees(issue.Assignees, update.AddAssignees, update.RemoveAssignees))))
+	}
+
+	d.print(fmt.Sprintf("would update #%d: %s", number, strings.Join(changes, ", ")), details...)
+	return nil
+}
+
+func (d *DryRun) Close(number int) error {
+	return d.setState(number, "close", internal.StateClosed)
+}
+
+func (d *DryRun) Reopen(number int) error {
+	return d.setState(number, "reopen", internal.StateOpen)
+}
+
+func (d *DryRun) Delete(number int) error {
+	issue, err := d.Get(number)
+	if err != nil {
+		return err
+	}
+	d.print(fmt.Sprintf("would delete #%d %q", number, issue.Title), "issue and comments removed permanently")
+	return nil
+}
+
+func (d *DryRun) AddComment(number int, body string) error {
+	if _, err := d.Get(number); err != nil {
+		return err
+	}
+	d.print(fmt.Sprintf("would comment on #%d", number), fmt.Sprintf("comment lines: %d", countLines(body)))
+	return nil
+}
+
+func (d *DryRun) Labels() ([]string, error) {
+	if d.inner == nil {
+		return nil, nil
+	}
+	return d.inner.Labels()
+}
+
+func (d *DryRun) CreateLabel(name, color, description string) error {
+	d.print(fmt.Sprintf("would create label %s", name), fmt.Sprintf("color: #%s, description: %q", color, description))
+	return nil
+}
+
+// CreatePullRequest prints the pull request instead of opening it. DryRun
+// plans pull requests for any backend, so commands can be tried without one.
+func (d *DryRun) CreatePullRequest(pr PullRequest) (string, error) {
+	base := pr.Base
+	if base == "" {
+		base = "(default branch)"
+	}
+	details := []string{fmt.Sprintf("%s → %s", pr.Head, base), fmt.Sprintf("body lines: %d", countLines(pr.Body))}
+	if pr.Draft {
+		details = append(details, "draft")
+	}
+	d.print(fmt.Sprintf("would open pull request %q", pr.Title), details...)
+	return "", nil
+}
+
+func (d *DryRun) setState(number int, verb, state string) error {
+	issue, err := d.Get(number)
+	if err != nil {
+		return err
+	}
+	d.print(fmt.Sprintf("would %s #%d %q", verb, number, issue.Title),
+		fmt.Sprintf("state: %s → %s", issue.State, state))
+	return nil
+}
+
+// print writes one planned call and its indented details; bulk commands call
+// the backend concurrently, so each call is written as a whole
+func (d *DryRun) print(call string, details ...string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	fmt.Fprintln(d.out, call)
+	for _, detail := range details {
+		fmt.Fprintln(d.out, "  "+detail)
+	}
+}
+
+func formatChanges(add, remove []string) string {
+	var parts []string
+	for _, name := range add {
+		parts = append(parts, "+"+name)
+	}
+	for _, name := range remove {
+		parts = append(parts, "-"+name)
+	}
+	return strings.Join(parts, " ")
+}
+
+func formatLabels(labels []internal.Label) string {
+	if len(labels) == 0 {
+		return "(none)"
+	}
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = label.Name
+	}
+	return strings.Join(names, ", ")
+}
+
+func formatUsers(users []internal.User) string {
+	if len(users) == 0 {
+		return "(none)"
+	}
+	logins := make([]string, len(users))
+	for i, user := range users {
+		logins[i] = user.Login
+	}
+	return strings.Join(logins, ", ")
+}
+
+func countLines(text string) int {
+	text = strings.TrimRight(text, "\n")
+	if text == "" {
+		return 0
+	}
+	return strings.Count(text, "\n") + 1
+}
@@ -0,0 +1,135 @@
+package backend
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestDryRunOutput(t *testing.T) {
+	title := "Fix login"
+	body := "first\nsecond\n"
+
+	tests := []struct {
+		name string
+		call func(d *DryRun) error
+		want string
+	}{
+		{
+			name: "create",
+			call: func(d *DryRun) error {
+				_, err := d.Create("New task", "one\ntwo", []string{"inbox", "P1"})
+				return err
+			},
+			want: "would create issue \"New task\"\n  labels: inbox, P1\n  body lines: 2\n",
+		},
+		{
+			name: "update labels",
+			call: func(d *DryRun) error {
+				return d.Update(1, Update{AddLabels: []string{"active"}, RemoveLabels: []string{"inbox"}})
+			},
+			want: "would update #1: +active -inbox\n  labels: P2, inbox → P2, active\n",
+		},
+		{
+			name: "update title and body",
+			call: func(d *DryRun) error {
+				return d.Update(1, Update{Title: &title, Body: &body})
+			},
+			want: "would update #1: title, body\n  title: \"Old title\" → \"Fix login\"\n  body lines: 0 → 2\n",
+		},
+		{
+			name: "assign",
+			call: func(d *DryRun) error {
+				return d.Update(1, Update{AddAssignees: []string{"ana"}})
+			},
+			want: "would update #1: assignees +ana\n  assignees: (none) → ana\n",
+		},
+		{
+			name: "close",
+			call: func(d *DryRun) error { return d.Close(1) },
+			want: "would close #1 \"Old title\"\n  state: OPEN → CLOSED\n",
+		},
+		{
+			name: "delete",
+			call: func(d *DryRun) error { return d.Delete(1) },
+			want: "would delete #1 \"Old title\"\n  issue and comments removed permanently\n",
+		},
+		{
+			name: "comment",
+			call: func(d *DryRun) error { return d.AddComment(1, "looks good") },
+			want: "would comment on #1\n  comment lines: 1\n",
+		},
+		{
+			name: "label",
+			call: func(d *DryRun) error { return d.CreateLabel("review", "1d76db", "Waiting") },
+			want: "would create label review\n  color: #1d76db, description: \"Waiting\"\n",
+		},
+		{
+			name: "draft pull request",
+			call: func(d *DryRun) error {
+				_, err := d.CreatePullRequest(PullRequest{Title: "Fix login", Body: "Closes #1", Head: "1-fix-login", Draft: true})
+				return err
+			},
+			want: "would open pull request \"Fix login\"\n  1-fix-login → (default branch)\n  body lines: 1\n  draft\n",
+		},
+		{
+			name: "missing issue",
+			call: func(d *DryRun) error { return d.Close(7) },
+			want: "",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			inner := NewMarkdown(t.TempDir())
+			if _, err := inner.Create("Old title", "", []string{"inbox", "P2"}); err != nil {
+				t.Fatal(err)
+			}
+			before, _ := inner.Get(1)
+
+			var out strings.Builder
+			err := tt.call(NewDryRun(inner, &out))
+			if tt.want == "" {
+				if err == nil {
+					t.Fatalf("expected an error, printed %q", out.String())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if out.String() != tt.want {
+				t.Errorf("output:\n%s\nwant:\n%s", out.String(), tt.want)
+			}
+
+			after, _ := inner.Get(1)
+			issues, _ := inner.List(ListAll)
+			if len(issues) != 1 || after.Title != before.Title || after.State != before.State ||
+				formatLabels(after.Labels) != formatLabels(before.Labels) {
+				t.Errorf("dry run changed the backend: %+v", issues)
+			}
+		})
+	}
+}
+
+func TestDryRunWithoutBackend(t *testing.T) {
+	var out strings.Builder
+	d := NewDryRun(nil, &out)
+
+	if _, err := d.Create("Plan", "", nil); err != nil {
+		t.Fatal(err)
+	}
+	// The issue it would create has number 0 and can be changed further
+	if err := d.Update(0, Update{AddLabels: []string{"active"}}); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Close(5); err != nil {
+		t.Fatal(err)
+	}
+
+	want := "would create issue \"Plan\"\n  labels: (none)\n  body lines: 0\n" +
+		"would update #0: +active\n  labels: (none) → active\n" +
+		"would close #5 \"\"\n  state: OPEN → CLOSED\n"
+	if out.String() != want {
+		t.Errorf("output:\n%s\nwant:\n%s", out.String(), want)
+	}
+}
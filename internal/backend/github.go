@@ -0,0 +1,259 @@
+package backend
+
+import (
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+const (
+	// Issues fetched by a list call; a list filling it is fetched again with
+	// twice the limit until everything fits (gh pages through the API itself)
+	maxIssueLimit = 1000
+
+	// Fields requested from gh for list and view calls
+	listFields = "number,title,labels,assignees,state,createdAt,updatedAt,closedAt,url"
+	viewFields = listFields + ",body,comments"
+
+	// Pull requests mentioning or closing an issue, from its timeline
+	linkedPullRequestsQuery = `query($owner: String!, $name: String!, $number: Int!) {
+  repository(owner: $owner, name: $name) {
+    issue(number: $number) {
+      timelineItems(itemTypes: [CROSS_REFERENCED_EVENT, CONNECTED_EVENT], first: 100) {
+        nodes {
+          ... on CrossReferencedEvent { source { ... on PullRequest { number title state url } } }
+          ... on ConnectedEvent { subject { ... on PullRequest { number title state url } } }
+        }
+      }
+    }
+  }
+}`
+)
+
+// GitHub is the default backend, storing tasks as GitHub Issues through the gh CLI.
+type GitHub struct {
+	Repo string // owner/name
+}
+
+// NewGitHub returns a backend for the given owner/name repository.
+func NewGitHub(repo string) *GitHub {
+	return &GitHub{Repo: repo}
+}
+
+func (g *GitHub) List(state string) ([]internal.Issue, error) {
+	return g.list(state, listFields)
+}
+
+func (g *GitHub) ListWithBodies(state string) ([]internal.Issue, error) {
+	return g.list(state, listFields+",body")
+}
+
+func (g *GitHub) list(state, fields string) ([]internal.Issue, error) {
+	for limit := maxIssueLimit; ; limit *= 2 {
+		output, err := g.gh("issue", "list",
+			"--state", state,
+			"--json", fields,
+			"--limit", strconv.Itoa(limit))
+		if err != nil {
+			return nil, err
+		}
+
+		var issues []internal.Issue
+		if err := json.Unmarshal(output, &issues); err != nil {
+			return nil, fmt.Errorf("failed to parse issues: %w", err)
+		}
+		if len(issues) < limit {
+			return issues, nil
+		}
+	}
+}
+
+func (g *GitHub) Get(number int) (internal.Issue, error) {
+	output, err := g.gh("issue", "view", strconv.Itoa(number), "--json", viewFields)
+	if err != nil {
+		return internal.Issue{}, err
+	}
+
+	var issue internal.Issue
+	if err := json.Unmarshal(output, &issue); err != nil {
+		return internal.Issue{}, fmt.Errorf("failed to parse issue: %w", err)
+	}
+	return issue, nil
+}
+
+func (g *GitHub) Create(title, body string, labels []string) (internal.Issue, error) {
+	output, err := g.gh("issue", "create",
+		"--title", title,
+		"--label", strings.Join(labels, ","),
+		"--body", body)
+	if err != nil {
+		return internal.Issue{}, err
+	}
+
+	// gh prints the URL of the new issue, which ends with its number
+	url := strings.TrimSpace(string(output))
+	number, err := strconv.Atoi(url[strings.LastIndex(url, "/")+1:])
+	if err != nil {
+		return internal.Issue{}, fmt.Errorf("unexpected output from gh: %s", url)
+	}
+
+	issue := internal.Issue{
+		Number: number,
+		Title:  title,
+		Body:   body,
+		State:  internal.StateOpen,
+		URL:    url,
+	}
+	for _, label := range labels {
+		issue.Labels = append(issue.Labels, internal.Label{Name: label})
+	}
+	return issue, nil
+}
+
+func (g *GitHub) Update(number int, update Update) error {
+	args := []string{"issue", "edit", strconv.Itoa(number)}
+	if update.Title != nil {
+		args = append(args, "--title", *update.Title)
+	}
+	if update.Body != nil {
+		args = append(args, "--body", *update.Body)
+	}
+	if len(update.AddLabels) > 0 {
+		args = append(args, "--add-label", strings.Join(update.AddLabels, ","))
+	}
+	if len(update.RemoveLabels) > 0 {
+		args = append(args, "--remove-label", strings.Join(update.RemoveLabels, ","))
+	}
+	if len(update.AddAssignees) > 0 {
+		args = append(args, "--add-assignee", strings.Join(update.AddAssignees, ","))
+	}
+	if len(update.RemoveAssignees) > 0 {
+		args = append(args, "--remove-assignee", strings.Join(update.RemoveAssignees, ","))
+	}
+
+	_, err := g.gh(args...)
+	return err
+}
+
+func (g *GitHub) Close(number int) error {
+	_, err := g.gh("issue", "close", strconv.Itoa(number))
+	return err
+}
+
+func (g *GitHub) Reopen(number int) error {
+	_, err := g.gh("issue", "reopen", strconv.Itoa(number))
+	return err
+}
+
+func (g *GitHub) Delete(number int) error {
+	_, err := g.gh("issue", "delete", strconv.Itoa(number), "--yes")
+	return err
+}
+
+func (g *GitHub) AddComment(number int, body string) error {
+	_, err := g.gh("issue", "comment", strconv.Itoa(number), "--body", body)
+	return err
+}
+
+func (g *GitHub) Labels() ([]string, error) {
+	output, err := g.gh("label", "list", "--json", "name", "--limit", strconv.Itoa(maxIssueLimit))
+	if err != nil {
+		return nil, err
+	}
+
+	var labels []internal.Label
+	if err := json.Unmarshal(output, &labels); err != nil {
+		return nil, fmt.Errorf("failed to parse labels: %w", err)
+	}
+
+	names := make([]string, len(labels))
+	for i, label := range labels {
+		names[i] = label.Name
+	}
+	return names, nil
+}
+
+func (g *GitHub) CreateLabel(name, color, description string) error {
+	_, err := g.gh("label", "create", name,
+		"--color", color,
+		"--description", description)
+	return err
+}
+
+func (g *GitHub) CreatePullRequest(pr PullRequest) (string, error) {
+	args := []string{"pr", "create",
+		"--title", pr.Title,
+		"--body", pr.Body,
+		"--head", pr.Head}
+	if pr.Base != "" {
+		args = append(args, "--base", pr.Base)
+	}
+	if pr.Draft {
+		args = append(args, "--draft")
+	}
+
+	output, err := g.gh(args...)
+	if err != nil {
+		return "", err
+	}
+
+	// gh may print progress first; the URL is the last line
+	lines := strings.Split(strings.TrimSpace(string(output)), "\n")
+	return strings.TrimSpace(lines[len(lines)-1]), nil
+}
+
+func (g *GitHub) LinkedPullRequests(number int) ([]LinkedPullRequest, error) {
+	owner, name, _ := strings.Cut(g.Repo, "/")
+	output, err := runGh("api", "graphql",
+		"-f", "query="+linkedPullRequestsQuery,
+		"-f", "owner="+owner,
+		"-f", "name="+name,
+		"-F", "number="+strconv.Itoa(number),
+		"--jq", "[.data.repository.issue.timelineItems.nodes[] | (.source // .subject) | select(.number != null)]")
+	if err != nil {
+		return nil, err
+	}
+
+	var found []LinkedPullRequest
+	if err := json.Unmarshal(output, &found); err != nil {
+		return nil, fmt.Errorf("failed to parse pull requests: %w", err)
+	}
+
+	// A pull request is referenced again by every commit and edit mentioning the issue
+	var prs []LinkedPullRequest
+	seen := map[int]bool{}
+	for _, pr := range found {
+		if !seen[pr.Number] {
+			seen[pr.Number] = true
+			prs = append(prs, pr)
+		}
+	}
+	return prs, nil
+}
+
+// gh runs a gh subcommand against the backend's repository and returns its stdout.
+func (g *GitHub) gh(args ...string) ([]byte, error) {
+	return runGh(append(args, "--repo", g.Repo)...)
+}
+
+// runGh runs gh and returns its stdout. On failure the error includes whatever
+// gh printed to stderr.
+func runGh(args ...string) ([]byte, error) {
+	cmd := exec.Command("gh", args...)
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w\n%s", err, msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
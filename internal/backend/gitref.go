@@ -0,0 +1,694 @@
+package backend
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os/exec"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+const (
+	// Ref namespace holding one ref per task, and where remote copies are fetched to
+	gitTaskRefPrefix   = "refs/ghtask/tasks/"
+	gitRemoteRefPrefix = "refs/ghtask/remotes/"
+
+	// Random bytes in a task ID (hex encoded in the ref name)
+	gitTaskIDBytes = 8
+
+	// Record separators used when reading operations back with git log
+	gitFieldSep  = "\x1f"
+	gitRecordSep = "\x1e"
+
+	// Operation times: RFC 3339 with fixed nanoseconds, so they sort as text
+	gitTimeFormat = "2006-01-02T15:04:05.000000000Z07:00"
+)
+
+// Operation types recorded in task commits
+const (
+	gitOpCreate  = "create"
+	gitOpEdit    = "edit"
+	gitOpState   = "state"
+	gitOpDelete  = "delete"
+	gitOpComment = "comment"
+	gitOpMerge   = "merge"
+)
+
+// GitRef stores tasks inside the git repository itself, similar to git-bug.
+// Every task is a ref under refs/ghtask/tasks/<id> pointing at a chain of
+// commits, each carrying one JSON operation in its message and an empty tree.
+// Tasks travel with `gt sync` (fetch + merge + push of the ref namespace) and
+// the current state is computed by replaying the operations in a deterministic
+// order (lamport clock, then time, then commit hash), so every collaborator
+// sees the same result once they hold the same commits.
+type GitRef struct {
+	gitDir    string
+	emptyTree string
+}
+
+// gitOp is a single operation on a task, stored as a commit message
+type gitOp struct {
+	Type     string   `json:"type"`
+	Task     string   `json:"task"`
+	Lamport  int      `json:"lamport,omitempty"`
+	Time     string   `json:"time,omitempty"`
+	Author   string   `json:"author,omitempty"`
+	Number   int      `json:"number,omitempty"`
+	Title    *string  `json:"title,omitempty"`
+	Body     *string  `json:"body,omitempty"`
+	Add      []string `json:"add,omitempty"`
+	Remove   []string `json:"remove,omitempty"`
+	Assign   []string `json:"assign,omitempty"`
+	Unassign []string `json:"unassign,omitempty"`
+	State    string   `json:"state,omitempty"`
+
+	hash string // commit carrying the operation, not serialized
+}
+
+// gitTask is the replayed state of a task
+type gitTask struct {
+	id      string
+	issue   internal.Issue
+	deleted bool
+	lamport int    // highest lamport clock seen in the task's history
+	created string // create commit hash, tie-breaker for number conflicts
+	claimed int    // number recorded in the history, before conflicts are resolved
+}
+
+// NewGitRef returns a backend storing tasks in the given git directory.
+// An empty gitDir uses the repository of the current working directory;
+// bare repositories are supported.
+func NewGitRef(gitDir string) (*GitRef, error) {
+	g := &GitRef{gitDir: gitDir}
+
+	if g.gitDir == "" {
+		output, err := g.git("", "rev-parse", "--absolute-git-dir")
+		if err != nil {
+			return nil, fmt.Errorf("not in a git repository")
+		}
+		g.gitDir = strings.TrimSpace(string(output))
+	}
+
+	return g, nil
+}
+
+func (g *GitRef) List(state string) ([]internal.Issue, error) {
+	tasks, err := g.load()
+	if err != nil {
+		return nil, err
+	}
+
+	var issues []internal.Issue
+	for _, task := range tasks {
+		if task.deleted || !matchesState(task.issue, state) {
+			continue
+		}
+		issues = append(issues, task.issue)
+	}
+	return issues, nil
+}
+
+func (g *GitRef) Get(number int) (internal.Issue, error) {
+	task, err := g.find(number)
+	if err != nil {
+		return internal.Issue{}, err
+	}
+	return task.issue, nil
+}
+
+func (g *GitRef) Create(title, body string, labels []string) (internal.Issue, error) {
+	tasks, err := g.load()
+	if err != nil {
+		return internal.Issue{}, err
+	}
+
+	number := 1
+	for _, task := range tasks {
+		if task.issue.Number >= number {
+			number = task.issue.Number + 1
+		}
+	}
+
+	id, err := newTaskID()
+	if err != nil {
+		return internal.Issue{}, err
+	}
+
+	op := gitOp{
+		Type:    gitOpCreate,
+		Task:    id,
+		Lamport: 1,
+		Number:  number,
+		Title:   &title,
+		Body:    &body,
+		Add:     labels,
+	}
+	if err := g.commitOp(op, ""); err != nil {
+		return internal.Issue{}, err
+	}
+
+	return g.Get(number)
+}
+
+func (g *GitRef) Update(number int, update Update) error {
+	return g.apply(number, gitOp{
+		Type:     gitOpEdit,
+		Title:    update.Title,
+		Body:     update.Body,
+		Add:      update.AddLabels,
+		Remove:   update.RemoveLabels,
+		Assign:   update.AddAssignees,
+		Unassign: update.RemoveAssignees,
+	})
+}
+
+func (g *GitRef) Close(number int) error {
+	return g.apply(number, gitOp{Type: gitOpState, State: internal.StateClosed})
+}
+
+func (g *GitRef) Reopen(number int) error {
+	return g.apply(number, gitOp{Type: gitOpState, State: internal.StateOpen})
+}
+
+// Delete records a tombstone rather than removing the ref, so the deletion
+// propagates to collaborators instead of the task being fetched back.
+func (g *GitRef) Delete(number int) error {
+	return g.apply(number, gitOp{Type: gitOpDelete})
+}
+
+func (g *GitRef) AddComment(number int, body string) error {
+	author, _ := g.git("", "config", "user.name")
+	return g.apply(number, gitOp{
+		Type:   gitOpComment,
+		Author: strings.TrimSpace(string(author)),
+		Body:   &body,
+	})
+}
+
+// Labels returns every label used by a task. Labels are free-form in this
+// backend, so there is nothing to create up front.
+func (g *GitRef) Labels() ([]string, error) {
+	tasks, err := g.load()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, task := range tasks {
+		for _, label := range task.issue.Labels {
+			if !seen[label.Name] {
+				seen[label.Name] = true
+				names = append(names, label.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (g *GitRef) CreateLabel(name, color, description string) error {
+	return nil
+}
+
+// Sync exchanges tasks with a git remote: remote task refs are fetched, merged
+// into the local ones (fast-forward when possible, merge commit otherwise) and
+// the result is pushed back.
+func (g *GitRef) Sync(remote string) (SyncResult, error) {
+	var result SyncResult
+	remotePrefix := gitRemoteRefPrefix + remote + "/"
+
+	refspec := "+" + gitTaskRefPrefix + "*:" + remotePrefix + "*"
+	if _, err := g.git("", "fetch", remote, refspec); err != nil {
+		return result, fmt.Errorf("failed to fetch tasks from %s: %w", remote, err)
+	}
+
+	remoteRefs, err := g.refs(remotePrefix)
+	if err != nil {
+		return result, err
+	}
+
+	for id, remoteHash := range remoteRefs {
+		localRef := gitTaskRefPrefix + id
+		localHash := g.resolve(localRef)
+
+		switch {
+		case localHash == "":
+			if err := g.updateRef(localRef, remoteHash, ""); err != nil {
+				return result, err
+			}
+			result.Fetched++
+		case localHash == remoteHash || g.isAncestor(remoteHash, localHash):
+			// Already have everything the remote has
+		case g.isAncestor(localHash, remoteHash):
+			if err := g.updateRef(localRef, remoteHash, localHash); err != nil {
+				return result, err
+			}
+			result.Updated++
+		default:
+			if err := g.merge(id, localHash, remoteHash); err != nil {
+				return result, err
+			}
+			result.Merged++
+		}
+	}
+
+	if result.Renumbered, err = g.renumberUnpublished(remoteRefs); err != nil {
+		return result, err
+	}
+
+	localRefs, err := g.refs(gitTaskRefPrefix)
+	if err != nil || len(localRefs) == 0 {
+		return result, err
+	}
+
+	refspec = gitTaskRefPrefix + "*:" + gitTaskRefPrefix + "*"
+	if _, err := g.git("", "push", remote, refspec); err != nil {
+		return result, fmt.Errorf("failed to push tasks to %s (run sync again if someone pushed meanwhile): %w", remote, err)
+	}
+
+	return result, nil
+}
+
+// apply appends an operation to the task with the given number
+func (g *GitRef) apply(number int, op gitOp) error {
+	task, err := g.find(number)
+	if err != nil {
+		return err
+	}
+
+	op.Task = task.id
+	op.Lamport = task.lamport + 1
+	return g.commitOp(op, g.resolve(gitTaskRefPrefix+task.id))
+}
+
+func (g *GitRef) find(number int) (*gitTask, error) {
+	tasks, err := g.load()
+	if err != nil {
+		return nil, err
+	}
+
+	for _, task := range tasks {
+		if task.issue.Number == number && !task.deleted {
+			return task, nil
+		}
+	}
+	return nil, fmt.Errorf("issue #%d not found", number)
+}
+
+// load replays the operations of every task ref and assigns issue numbers
+func (g *GitRef) load() ([]*gitTask, error) {
+	refs, err := g.refs(gitTaskRefPrefix)
+	if err != nil || len(refs) == 0 {
+		return nil, err
+	}
+
+	var revs strings.Builder
+	for _, hash := range refs {
+		revs.WriteString(hash + "\n")
+	}
+
+	output, err := g.git(revs.String(), "log", "--stdin",
+		"--format=%H"+gitFieldSep+"%B"+gitRecordSep)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tasks: %w", err)
+	}
+
+	opsByTask := map[string][]gitOp{}
+	for _, record := range strings.Split(string(output), gitRecordSep) {
+		hash, message, found := strings.Cut(strings.TrimSpace(record), gitFieldSep)
+		if !found {
+			continue
+		}
+
+		var op gitOp
+		if err := json.Unmarshal([]byte(message), &op); err != nil || op.Task == "" {
+			continue // not a task operation
+		}
+		op.hash = hash
+		opsByTask[op.Task] = append(opsByTask[op.Task], op)
+	}
+
+	var tasks []*gitTask
+	for id, ops := range opsByTask {
+		if task := replay(id, ops); task != nil {
+			tasks = append(tasks, task)
+		}
+	}
+
+	assignNumbers(tasks)
+	return tasks, nil
+}
+
+// replay applies a task's operations in deterministic order
+func replay(id string, ops []gitOp) *gitTask {
+	sort.Slice(ops, func(i, j int) bool {
+		if ops[i].Lamport != ops[j].Lamport {
+			return ops[i].Lamport < ops[j].Lamport
+		}
+		if ops[i].Time != ops[j].Time {
+			return ops[i].Time < ops[j].Time
+		}
+		return ops[i].hash < ops[j].hash
+	})
+
+	var task *gitTask
+	for _, op := range ops {
+		if op.Type == gitOpCreate {
+			if task != nil {
+				continue // only the first create counts
+			}
+			task = &gitTask{
+				id:      id,
+				created: op.hash,
+				issue: internal.Issue{
+					Number:    op.Number,
+					State:     internal.StateOpen,
+					CreatedAt: op.Time,
+					UpdatedAt: op.Time,
+				},
+			}
+		}
+		if task == nil {
+			continue // operations without a create are ignored
+		}
+
+		task.lamport = max(task.lamport, op.Lamport)
+
+		switch op.Type {
+		case gitOpCreate, gitOpEdit:
+			if op.Number > 0 {
+				task.issue.Number = op.Number
+			}
+			if op.Title != nil {
+				task.issue.Title = *op.Title
+			}
+			if op.Body != nil {
+				task.issue.Body = *op.Body
+			}
+			task.issue.Labels = applyLabels(task.issue.Labels, op.Add, op.Remove)
+			task.issue.Assignees = applyAssignees(task.issue.Assignees, op.Assign, op.Unassign)
+		case gitOpState:
+			task.issue.State = op.State
+			task.issue.ClosedAt = ""
+			if op.State == internal.StateClosed {
+				task.issue.ClosedAt = op.Time
+			}
+		case gitOpComment:
+			if op.Body != nil {
+				task.issue.Comments = append(task.issue.Comments, internal.Comment{
+					Author:    internal.User{Login: op.Author},
+					Body:      *op.Body,
+					CreatedAt: op.Time,
+				})
+			}
+		case gitOpDelete:
+			task.deleted = true
+		case gitOpMerge:
+			continue
+		}
+
+		if op.Time > task.issue.UpdatedAt {
+			task.issue.UpdatedAt = op.Time
+		}
+	}
+
+	if task != nil {
+		task.claimed = task.issue.Number
+	}
+	return task
+}
+
+// assignNumbers resolves number conflicts left between tasks that were pushed
+// concurrently by different collaborators (Sync renumbers tasks that were not
+// pushed yet): the earliest task (by creation time, then create commit hash)
+// keeps the number, the others are renumbered after the highest number in
+// use. Everyone holding the same tasks computes the same numbers.
+func assignNumbers(tasks []*gitTask) {
+	sort.Slice(tasks, func(i, j int) bool {
+		a, b := tasks[i], tasks[j]
+		if a.issue.CreatedAt != b.issue.CreatedAt {
+			return a.issue.CreatedAt < b.issue.CreatedAt
+		}
+		return a.created < b.created
+	})
+
+	used := map[int]bool{}
+	highest := 0
+	var conflicts []*gitTask
+
+	for _, task := range tasks {
+		if used[task.issue.Number] || task.issue.Number <= 0 {
+			conflicts = append(conflicts, task)
+			continue
+		}
+		used[task.issue.Number] = true
+		highest = max(highest, task.issue.Number)
+	}
+
+	for _, task := range conflicts {
+		highest++
+		task.issue.Number = highest
+	}
+}
+
+func applyLabels(labels []internal.Label, add, remove []string) []internal.Label {
+	var result []internal.Label
+	for _, label := range labels {
+		if !containsFold(remove, label.Name) {
+			result = append(result, label)
+		}
+	}
+
+	for _, name := range add {
+		exists := false
+		for _, label := range result {
+			if strings.EqualFold(label.Name, name) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			result = append(result, internal.Label{Name: name})
+		}
+	}
+	return result
+}
+
+func applyAssignees(assignees []internal.User, add, remove []string) []internal.User {
+	var result []internal.User
+	for _, user := range assignees {
+		if !containsFold(remove, user.Login) {
+			result = append(result, user)
+		}
+	}
+
+	for _, login := range add {
+		exists := false
+		for _, user := range result {
+			if strings.EqualFold(user.Login, login) {
+				exists = true
+				break
+			}
+		}
+		if !exists {
+			result = append(result, internal.User{Login: login})
+		}
+	}
+	return result
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// matchesState reports whether an issue is in a List state
+func matchesState(issue internal.Issue, state string) bool {
+	switch state {
+	case ListOpen:
+		return issue.State == internal.StateOpen
+	case ListClosed:
+		return issue.State == internal.StateClosed
+	}
+	return true
+}
+
+// renumberUnpublished gives the local tasks that were never pushed a new
+// number when a task on the remote already claims theirs, recording it in
+// their history, so a task keeps its number once it has been pushed
+func (g *GitRef) renumberUnpublished(remoteRefs map[string]string) ([]Renumbering, error) {
+	tasks, err := g.load()
+	if err != nil {
+		return nil, err
+	}
+
+	// Numbers shown now are resolved against the unpublished tasks too, so
+	// count the recorded numbers, with conflicts among the published tasks
+	// resolved between themselves
+	var remote []*gitTask
+	published := map[int]bool{}
+	highest := 0
+	for _, task := range tasks {
+		if _, ok := remoteRefs[task.id]; ok {
+			published[task.claimed] = true
+			numbered := *task
+			numbered.issue.Number = task.claimed
+			remote = append(remote, &numbered)
+		}
+		highest = max(highest, task.claimed)
+	}
+	assignNumbers(remote)
+	for _, task := range remote {
+		highest = max(highest, task.issue.Number)
+	}
+
+	// load sorted the tasks by creation, so renumbering is stable
+	var renumbered []Renumbering
+	for _, task := range tasks {
+		if _, ok := remoteRefs[task.id]; ok || !published[task.claimed] {
+			continue
+		}
+
+		highest++
+		op := gitOp{Type: gitOpEdit, Task: task.id, Lamport: task.lamport + 1, Number: highest}
+		if err := g.commitOp(op, g.resolve(gitTaskRefPrefix+task.id)); err != nil {
+			return renumbered, err
+		}
+		if !task.deleted {
+			renumbered = append(renumbered, Renumbering{From: task.claimed, To: highest})
+		}
+	}
+	return renumbered, nil
+}
+
+// commitOp records an operation as a new commit on top of parent (empty for a new task)
+func (g *GitRef) commitOp(op gitOp, parent string) error {
+	op.Time = time.Now().UTC().Format(gitTimeFormat)
+	message, err := json.Marshal(op)
+	if err != nil {
+		return err
+	}
+
+	args := []string{"commit-tree", "-F", "-"}
+	if parent != "" {
+		args = append(args, "-p", parent)
+	}
+	hash, err := g.commit(string(message), args...)
+	if err != nil {
+		return err
+	}
+
+	return g.updateRef(gitTaskRefPrefix+op.Task, hash, parent)
+}
+
+// merge joins two diverged histories of a task with an operation-less commit
+func (g *GitRef) merge(id, local, remote string) error {
+	message, err := json.Marshal(gitOp{Type: gitOpMerge, Task: id})
+	if err != nil {
+		return err
+	}
+
+	hash, err := g.commit(string(message), "commit-tree", "-F", "-", "-p", local, "-p", remote)
+	if err != nil {
+		return err
+	}
+	return g.updateRef(gitTaskRefPrefix+id, hash, local)
+}
+
+// commit runs commit-tree with the empty tree and returns the new commit hash
+func (g *GitRef) commit(message string, args ...string) (string, error) {
+	if g.emptyTree == "" {
+		output, err := g.git("", "hash-object", "-t", "tree", "-w", "--stdin")
+		if err != nil {
+			return "", fmt.Errorf("failed to create empty tree: %w", err)
+		}
+		g.emptyTree = strings.TrimSpace(string(output))
+	}
+
+	args = append(args, g.emptyTree)
+	output, err := g.git(message, args...)
+	if err != nil {
+		return "", fmt.Errorf("failed to record task change: %w", err)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// updateRef moves ref to hash, failing if it no longer points at old
+// (an empty old requires the ref not to exist yet)
+func (g *GitRef) updateRef(ref, hash, old string) error {
+	if _, err := g.git("", "update-ref", ref, hash, old); err != nil {
+		return fmt.Errorf("failed to update %s (changed concurrently?): %w", ref, err)
+	}
+	return nil
+}
+
+// refs returns the refs under prefix, keyed by the name after the prefix
+func (g *GitRef) refs(prefix string) (map[string]string, error) {
+	output, err := g.git("", "for-each-ref", "--format=%(objectname) %(refname)", prefix)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list task refs: %w", err)
+	}
+
+	refs := map[string]string{}
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		hash, ref, found := strings.Cut(line, " ")
+		if found {
+			refs[strings.TrimPrefix(ref, prefix)] = hash
+		}
+	}
+	return refs, nil
+}
+
+func (g *GitRef) resolve(ref string) string {
+	output, err := g.git("", "rev-parse", "--verify", "--quiet", ref)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+func (g *GitRef) isAncestor(ancestor, descendant string) bool {
+	_, err := g.git("", "merge-base", "--is-ancestor", ancestor, descendant)
+	return err == nil
+}
+
+// git runs a git command against the backend's repository, feeding stdin if non-empty
+func (g *GitRef) git(stdin string, args ...string) ([]byte, error) {
+	if g.gitDir != "" {
+		args = append([]string{"--git-dir", g.gitDir}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+
+	output, err := cmd.Output()
+	if err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return nil, fmt.Errorf("%w\n%s", err, msg)
+		}
+		return nil, err
+	}
+	return output, nil
+}
+
+func newTaskID() (string, error) {
+	buf := make([]byte, gitTaskIDBytes)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate task id: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
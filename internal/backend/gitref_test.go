@@ -0,0 +1,198 @@
+package backend
+
+import (
+	"fmt"
+	"os/exec"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+// newGitRefClones returns two collaborators sharing a bare remote named origin
+func newGitRefClones(t *testing.T) (*GitRef, *GitRef) {
+	t.Helper()
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not installed")
+	}
+	t.Setenv("GIT_CONFIG_GLOBAL", filepath.Join(t.TempDir(), "gitconfig"))
+	t.Setenv("GIT_AUTHOR_NAME", "gt test")
+	t.Setenv("GIT_AUTHOR_EMAIL", "gt@example.com")
+	t.Setenv("GIT_COMMITTER_NAME", "gt test")
+	t.Setenv("GIT_COMMITTER_EMAIL", "gt@example.com")
+
+	dir := t.TempDir()
+	remote := filepath.Join(dir, "remote.git")
+	gitInitBare(t, remote)
+
+	var clones []*GitRef
+	for _, name := range []string{"a.git", "b.git"} {
+		path := filepath.Join(dir, name)
+		gitInitBare(t, path)
+		if output, err := exec.Command("git", "--git-dir", path, "remote", "add", "origin", remote).CombinedOutput(); err != nil {
+			t.Fatalf("git remote add: %v\n%s", err, output)
+		}
+		g, err := NewGitRef(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		clones = append(clones, g)
+	}
+	return clones[0], clones[1]
+}
+
+func gitInitBare(t *testing.T, path string) {
+	t.Helper()
+	if output, err := exec.Command("git", "init", "--bare", "--quiet", path).CombinedOutput(); err != nil {
+		t.Fatalf("git init --bare: %v\n%s", err, output)
+	}
+}
+
+func mustCreate(t *testing.T, g *GitRef, title string, labels ...string) internal.Issue {
+	t.Helper()
+	issue, err := g.Create(title, "", labels)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issue
+}
+
+func mustSync(t *testing.T, g *GitRef) SyncResult {
+	t.Helper()
+	result, err := g.Sync("origin")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return result
+}
+
+// gitRefState describes the tasks as "#N title [labels]" lines
+func gitRefState(t *testing.T, g *GitRef) []string {
+	t.Helper()
+	issues, err := g.List(ListAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var lines []string
+	for _, issue := range issues {
+		var labels []string
+		for _, label := range issue.Labels {
+			labels = append(labels, label.Name)
+		}
+		sort.Strings(labels)
+		line := fmt.Sprintf("#%d %s [%s]", issue.Number, issue.Title, strings.Join(labels, " "))
+		if issue.State == internal.StateClosed {
+			line += " closed"
+		}
+		lines = append(lines, line)
+	}
+	sort.Strings(lines)
+	return lines
+}
+
+func TestGitRefSync(t *testing.T) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, a, b *GitRef)
+		want []string
+	}{
+		{
+			name: "tasks travel to collaborators",
+			run: func(t *testing.T, a, b *GitRef) {
+				mustCreate(t, a, "first", "P1")
+				mustCreate(t, a, "second", "P2")
+				mustSync(t, a)
+				if result := mustSync(t, b); result.Fetched != 2 {
+					t.Errorf("fetched %d tasks, want 2", result.Fetched)
+				}
+			},
+			want: []string{"#1 first [P1]", "#2 second [P2]"},
+		},
+		{
+			name: "a pushed task keeps its number",
+			run: func(t *testing.T, a, b *GitRef) {
+				mustCreate(t, b, "from b")
+				mustCreate(t, a, "from a") // created later, but pushed first
+				mustSync(t, a)
+
+				result := mustSync(t, b)
+				if want := []Renumbering{{From: 1, To: 2}}; !reflect.DeepEqual(result.Renumbered, want) {
+					t.Errorf("renumbered %v, want %v", result.Renumbered, want)
+				}
+			},
+			want: []string{"#1 from a []", "#2 from b []"},
+		},
+		{
+			name: "numbers stay put after renumbering",
+			run: func(t *testing.T, a, b *GitRef) {
+				mustCreate(t, a, "from a")
+				mustCreate(t, b, "from b")
+				mustSync(t, a)
+				mustSync(t, b)
+				mustSync(t, a)
+				mustCreate(t, a, "later")
+			},
+			want: []string{"#1 from a []", "#2 from b []", "#3 later []"},
+		},
+		{
+			name: "concurrent edits merge",
+			run: func(t *testing.T, a, b *GitRef) {
+				mustCreate(t, a, "task", "inbox")
+				mustSync(t, a)
+				mustSync(t, b)
+
+				title := "renamed"
+				if err := a.Update(1, Update{Title: &title}); err != nil {
+					t.Fatal(err)
+				}
+				if err := b.Update(1, Update{AddLabels: []string{"active"}, RemoveLabels: []string{"inbox"}}); err != nil {
+					t.Fatal(err)
+				}
+				mustSync(t, a)
+				if result := mustSync(t, b); result.Merged != 1 {
+					t.Errorf("merged %d tasks, want 1", result.Merged)
+				}
+			},
+			want: []string{"#1 renamed [active]"},
+		},
+		{
+			name: "deletions propagate",
+			run: func(t *testing.T, a, b *GitRef) {
+				mustCreate(t, a, "keep")
+				mustCreate(t, a, "drop")
+				mustSync(t, a)
+				mustSync(t, b)
+				if err := b.Delete(2); err != nil {
+					t.Fatal(err)
+				}
+				if err := b.Close(1); err != nil {
+					t.Fatal(err)
+				}
+				mustSync(t, b)
+			},
+			want: []string{"#1 keep [] closed"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a, b := newGitRefClones(t)
+			tt.run(t, a, b)
+
+			// Bring both collaborators up to date
+			mustSync(t, a)
+			mustSync(t, b)
+			mustSync(t, a)
+
+			for name, g := range map[string]*GitRef{"a": a, "b": b} {
+				if got := gitRefState(t, g); !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("%s sees %q, want %q", name, got, tt.want)
+				}
+			}
+		})
+	}
+}
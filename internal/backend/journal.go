@@ -0,0 +1,328 @@
+package backend
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+// Operations recorded by Journal
+const (
+	JournalCreate = "create"
+	JournalUpdate = "update"
+	JournalClose  = "close"
+	JournalReopen = "reopen"
+	JournalDelete = "delete"
+)
+
+// JournalEntry is one recorded change. Entries made by the same gt
+// invocation share a group, so a bulk command is undone as a whole.
+type JournalEntry struct {
+	Group   string          `json:"group"`
+	Command string          `json:"command"`
+	Time    string          `json:"time"`
+	Op      string          `json:"op"`
+	Number  int             `json:"number"`
+	Title   string          `json:"title"`
+	Before  *internal.Issue `json:"before,omitempty"` // state before the change (full issue for deletes)
+	After   *internal.Issue `json:"after,omitempty"`  // state right after it, to notice later changes
+	Update  *Update         `json:"update,omitempty"`
+}
+
+// Journal wraps a backend and records every successful change together with
+// the issue's prior state, so that it can be reverted later (see Revert).
+// Added comments and newly created labels are not recorded, as backends
+// cannot remove them.
+type Journal struct {
+	inner   Backend
+	group   string
+	command string
+	record  func(JournalEntry)
+	mu      sync.Mutex
+}
+
+// NewJournal returns a backend passing calls to inner and handing an entry
+// to record after each change
+func NewJournal(inner Backend, group, command string, record func(JournalEntry)) *Journal {
+	return &Journal{inner: inner, group: group, command: command, record: record}
+}
+
+func (j *Journal) List(state string) ([]internal.Issue, error) {
+	return j.inner.List(state)
+}
+
+func (j *Journal) Get(number int) (internal.Issue, error) {
+	return j.inner.Get(number)
+}
+
+func (j *Journal) Create(title, body string, labels []string) (internal.Issue, error) {
+	issue, err := j.inner.Create(title, body, labels)
+	if err == nil {
+		j.add(JournalEntry{Op: JournalCreate, Number: issue.Number, Title: title, After: j.current(issue.Number)})
+	}
+	return issue, err
+}
+
+func (j *Journal) Update(number int, update Update) error {
+	return j.change(JournalUpdate, number, &update, func() error {
+		return j.inner.Update(number, update)
+	})
+}
+
+func (j *Journal) Close(number int) error {
+	return j.change(JournalClose, number, nil, func() error {
+		return j.inner.Close(number)
+	})
+}
+
+func (j *Journal) Reopen(number int) error {
+	return j.change(JournalReopen, number, nil, func() error {
+		return j.inner.Reopen(number)
+	})
+}
+
+func (j *Journal) Delete(number int) error {
+	return j.change(JournalDelete, number, nil, func() error {
+		return j.inner.Delete(number)
+	})
+}
+
+func (j *Journal) AddComment(number int, body string) error {
+	return j.inner.AddComment(number, body)
+}
+
+func (j *Journal) Labels() ([]string, error) {
+	return j.inner.Labels()
+}
+
+func (j *Journal) CreateLabel(name, color, description string) error {
+	return j.inner.CreateLabel(name, color, description)
+}
+
+// change fetches the issue, applies the change and records it with the
+// prior state and, unless the issue is gone, the resulting one
+func (j *Journal) change(op string, number int, update *Update, apply func() error) error {
+	before, err := j.inner.Get(number)
+	if err != nil {
+		return err
+	}
+	if err := apply(); err != nil {
+		return err
+	}
+
+	var after *internal.Issue
+	if op != JournalDelete {
+		after = j.current(number)
+	}
+	j.add(JournalEntry{Op: op, Number: number, Title: before.Title, Before: &before, After: after, Update: update})
+	return nil
+}
+
+// current returns the issue as it is now, or nil if it cannot be read
+func (j *Journal) current(number int) *internal.Issue {
+	issue, err := j.inner.Get(number)
+	if err != nil {
+		return nil
+	}
+	return &issue
+}
+
+func (j *Journal) add(entry JournalEntry) {
+	entry.Group = j.group
+	entry.Command = j.command
+	entry.Time = time.Now().UTC().Format(time.RFC3339)
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	j.record(entry)
+}
+
+// ChangedSince lists the fields of current (title, body, state, labels,
+// assignees) that differ from the state recorded after the change, i.e. what
+// someone changed since. Entries without a recorded state report nothing.
+func (e JournalEntry) ChangedSince(current internal.Issue) []string {
+	if e.After == nil {
+		return nil
+	}
+
+	var changed []string
+	if current.Title != e.After.Title {
+		changed = append(changed, "title")
+	}
+	if strings.TrimSpace(current.Body) != strings.TrimSpace(e.After.Body) {
+		changed = append(changed, "body")
+	}
+	if !strings.EqualFold(current.State, e.After.State) {
+		changed = append(changed, "state")
+	}
+
+	var labels, recorded []string
+	for _, label := range current.Labels {
+		labels = append(labels, label.Name)
+	}
+	for _, label := range e.After.Labels {
+		recorded = append(recorded, label.Name)
+	}
+	if !sameNames(labels, recorded) {
+		changed = append(changed, "labels")
+	}
+
+	var assignees []string
+	recorded = nil
+	for _, user := range current.Assignees {
+		assignees = append(assignees, user.Login)
+	}
+	for _, user := range e.After.Assignees {
+		recorded = append(recorded, user.Login)
+	}
+	if !sameNames(assignees, recorded) {
+		changed = append(changed, "assignees")
+	}
+	return changed
+}
+
+// sameNames reports whether two lists hold the same names, ignoring order and case
+func sameNames(a, b []string) bool {
+	for _, name := range a {
+		if !containsFold(b, name) {
+			return false
+		}
+	}
+	for _, name := range b {
+		if !containsFold(a, name) {
+			return false
+		}
+	}
+	return true
+}
+
+// Revert undoes the entry on b and describes what was done. Created issues
+// are deleted, closes and reopens are flipped, updated fields and label or
+// assignee changes are restored, and deleted issues are recreated from the
+// recorded content under a new number, which is returned.
+func (e JournalEntry) Revert(b Backend) (int, string, error) {
+	switch e.Op {
+	case JournalCreate:
+		if err := b.Delete(e.Number); err != nil {
+			return 0, "", err
+		}
+		return e.Number, fmt.Sprintf("Deleted #%d: %s", e.Number, e.Title), nil
+
+	case JournalClose:
+		if err := b.Reopen(e.Number); err != nil {
+			return 0, "", err
+		}
+		return e.Number, fmt.Sprintf("Reopened #%d: %s", e.Number, e.Title), nil
+
+	case JournalReopen:
+		if err := b.Close(e.Number); err != nil {
+			return 0, "", err
+		}
+		return e.Number, fmt.Sprintf("Closed #%d: %s", e.Number, e.Title), nil
+
+	case JournalUpdate:
+		if e.Before == nil || e.Update == nil {
+			return 0, "", fmt.Errorf("entry has no recorded state")
+		}
+		if err := b.Update(e.Number, inverseUpdate(*e.Before, *e.Update)); err != nil {
+			return 0, "", err
+		}
+		return e.Number, fmt.Sprintf("Restored #%d: %s", e.Number, e.Title), nil
+
+	case JournalDelete:
+		if e.Before == nil {
+			return 0, "", fmt.Errorf("entry has no recorded content")
+		}
+		number, err := recreate(b, *e.Before)
+		if err != nil {
+			return number, "", err
+		}
+		return number, fmt.Sprintf("Recreated #%d as #%d: %s", e.Number, number, e.Title), nil
+	}
+	return 0, "", fmt.Errorf("unknown operation: %s", e.Op)
+}
+
+// inverseUpdate restores the changed fields of before. Only labels and
+// assignees the update actually added or removed are touched.
+func inverseUpdate(before internal.Issue, update Update) Update {
+	var inverse Update
+	if update.Title != nil {
+		inverse.Title = &before.Title
+	}
+	if update.Body != nil {
+		inverse.Body = &before.Body
+	}
+
+	var labels, assignees []string
+	for _, label := range before.Labels {
+		labels = append(labels, label.Name)
+	}
+	for _, user := range before.Assignees {
+		assignees = append(assignees, user.Login)
+	}
+
+	inverse.AddLabels, inverse.RemoveLabels = invertChanges(labels, update.AddLabels, update.RemoveLabels)
+	inverse.AddAssignees, inverse.RemoveAssignees = invertChanges(assignees, update.AddAssignees, update.RemoveAssignees)
+	return inverse
+}
+
+// invertChanges returns what to add back (removed names that were present)
+// and what to remove again (added names that were absent)
+func invertChanges(present, added, removed []string) ([]string, []string) {
+	var add, remove []string
+	for _, name := range removed {
+		if containsFold(present, name) {
+			add = append(add, name)
+		}
+	}
+	for _, name := range added {
+		if !containsFold(present, name) {
+			remove = append(remove, name)
+		}
+	}
+	return add, remove
+}
+
+// recreate creates a deleted issue again with its labels, assignees, comments
+// and state, returning the new number
+func recreate(b Backend, issue internal.Issue) (int, error) {
+	var labels []string
+	for _, label := range issue.Labels {
+		labels = append(labels, label.Name)
+	}
+
+	created, err := b.Create(issue.Title, issue.Body, labels)
+	if err != nil {
+		return 0, err
+	}
+
+	var failures []string
+	if len(issue.Assignees) > 0 {
+		var logins []string
+		for _, user := range issue.Assignees {
+			logins = append(logins, user.Login)
+		}
+		if err := b.Update(created.Number, Update{AddAssignees: logins}); err != nil {
+			failures = append(failures, "assignees: "+err.Error())
+		}
+	}
+	for _, comment := range issue.Comments {
+		body := fmt.Sprintf("_Originally posted by @%s on %s_\n\n%s", comment.Author.Login, comment.CreatedAt, comment.Body)
+		if err := b.AddComment(created.Number, body); err != nil {
+			failures = append(failures, "comment: "+err.Error())
+		}
+	}
+	if issue.State == internal.StateClosed {
+		if err := b.Close(created.Number); err != nil {
+			failures = append(failures, "close: "+err.Error())
+		}
+	}
+
+	if len(failures) > 0 {
+		return created.Number, fmt.Errorf("recreated as #%d, but: %s", created.Number, strings.Join(failures, "; "))
+	}
+	return created.Number, nil
+}
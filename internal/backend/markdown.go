@@ -0,0 +1,382 @@
+package backend
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+const (
+	// Directory holding task files, relative to the repository root
+	MarkdownDir = ".tasks"
+
+	// Permissions for the task directory and files
+	markdownDirPerms  = 0755
+	markdownFilePerms = 0644
+
+	// Priority used when a task file has no priority field
+	markdownDefaultPriority = "P2"
+
+	// Marker opening each comment appended below the body
+	markdownCommentMarker = "<!-- gt:comment "
+
+	// File in the task directory holding the highest number ever used, so
+	// numbers of deleted tasks are not given out again
+	markdownCounterFile = ".last-number"
+)
+
+// Comment markers carry Go-quoted author and creation time
+var markdownCommentRe = regexp.MustCompile(`(?m)^<!-- gt:comment author=("(?:[^"\\]|\\.)*") created=("(?:[^"\\]|\\.)*") -->\n`)
+
+// Markdown stores every task as <number>.md in a directory, with YAML
+// frontmatter holding the metadata and the markdown body below it:
+//
+//	---
+//	title: Fix auth bug
+//	priority: P1
+//	state: open
+//	labels: [inbox, active]
+//	assignees: [octocat]
+//	created: 2025-01-02T15:04:05Z
+//	updated: 2025-01-02T15:04:05Z
+//	---
+//
+//	Body text
+//
+//	<!-- gt:comment author="octocat" created="2025-01-03T10:00:00Z" -->
+//	Comment text
+//
+// Task files are plain text, so they can be edited by hand, reviewed in
+// diffs and committed alongside the code.
+type Markdown struct {
+	dir string
+}
+
+// NewMarkdown returns a backend storing tasks in dir. An empty dir uses
+// .tasks/ at the root of the current git repository (or the current
+// directory outside a repository).
+func NewMarkdown(dir string) *Markdown {
+	if dir == "" {
+		root := "."
+		if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+			root = strings.TrimSpace(string(output))
+		}
+		dir = filepath.Join(root, MarkdownDir)
+	}
+	return &Markdown{dir: dir}
+}
+
+func (m *Markdown) List(state string) ([]internal.Issue, error) {
+	issues, err := m.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var filtered []internal.Issue
+	for _, issue := range issues {
+		if matchesState(issue, state) {
+			filtered = append(filtered, issue)
+		}
+	}
+	return filtered, nil
+}
+
+func (m *Markdown) Get(number int) (internal.Issue, error) {
+	content, err := os.ReadFile(m.path(number))
+	if os.IsNotExist(err) {
+		return internal.Issue{}, fmt.Errorf("issue #%d not found", number)
+	}
+	if err != nil {
+		return internal.Issue{}, err
+	}
+	return parseTaskFile(number, string(content)), nil
+}
+
+func (m *Markdown) Create(title, body string, labels []string) (internal.Issue, error) {
+	if err := os.MkdirAll(m.dir, markdownDirPerms); err != nil {
+		return internal.Issue{}, fmt.Errorf("failed to create %s: %w", m.dir, err)
+	}
+
+	issues, err := m.readAll()
+	if err != nil {
+		return internal.Issue{}, err
+	}
+
+	number := m.lastNumber() + 1
+	for _, issue := range issues {
+		if issue.Number >= number {
+			number = issue.Number + 1
+		}
+	}
+
+	now := time.Now().UTC().Format(time.RFC3339)
+	issue := internal.Issue{
+		Number:    number,
+		Title:     title,
+		Body:      body,
+		State:     internal.StateOpen,
+		Labels:    applyLabels(nil, labels, nil),
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+
+	// O_EXCL guards against two gt processes picking the same number
+	file, err := os.OpenFile(m.path(number), os.O_WRONLY|os.O_CREATE|os.O_EXCL, markdownFilePerms)
+	if err != nil {
+		return internal.Issue{}, fmt.Errorf("failed to create task file: %w", err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(formatTaskFile(issue)); err != nil {
+		return internal.Issue{}, fmt.Errorf("failed to write task file: %w", err)
+	}
+	if err := m.recordNumber(number); err != nil {
+		return issue, err
+	}
+	return issue, nil
+}
+
+func (m *Markdown) Update(number int, update Update) error {
+	return m.modify(number, func(issue *internal.Issue) {
+		if update.Title != nil {
+			issue.Title = *update.Title
+		}
+		if update.Body != nil {
+			issue.Body = *update.Body
+		}
+		issue.Labels = applyLabels(issue.Labels, update.AddLabels, update.RemoveLabels)
+		issue.Assignees = applyAssignees(issue.Assignees, update.AddAssignees, update.RemoveAssignees)
+	})
+}
+
+func (m *Markdown) Close(number int) error {
+	return m.modify(number, func(issue *internal.Issue) {
+		issue.State = internal.StateClosed
+		issue.ClosedAt = time.Now().UTC().Format(time.RFC3339)
+	})
+}
+
+func (m *Markdown) Reopen(number int) error {
+	return m.modify(number, func(issue *internal.Issue) {
+		issue.State = internal.StateOpen
+		issue.ClosedAt = ""
+	})
+}
+
+// Delete removes the task file, recording its number as used (tasks may
+// predate the counter or be created by hand)
+func (m *Markdown) Delete(number int) error {
+	err := os.Remove(m.path(number))
+	if os.IsNotExist(err) {
+		return fmt.Errorf("issue #%d not found", number)
+	}
+	if err != nil {
+		return err
+	}
+	return m.recordNumber(number)
+}
+
+func (m *Markdown) AddComment(number int, body string) error {
+	author := ""
+	if output, err := exec.Command("git", "config", "user.name").Output(); err == nil {
+		author = strings.TrimSpace(string(output))
+	}
+
+	return m.modify(number, func(issue *internal.Issue) {
+		issue.Comments = append(issue.Comments, internal.Comment{
+			Author:    internal.User{Login: author},
+			Body:      body,
+			CreatedAt: time.Now().UTC().Format(time.RFC3339),
+		})
+	})
+}
+
+// Labels returns every label used by a task file. Labels are free-form in
+// this backend, so there is nothing to create up front.
+func (m *Markdown) Labels() ([]string, error) {
+	issues, err := m.readAll()
+	if err != nil {
+		return nil, err
+	}
+
+	seen := map[string]bool{}
+	var names []string
+	for _, issue := range issues {
+		for _, label := range issue.Labels {
+			if !seen[label.Name] {
+				seen[label.Name] = true
+				names = append(names, label.Name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+func (m *Markdown) CreateLabel(name, color, description string) error {
+	return nil
+}
+
+func (m *Markdown) modify(number int, change func(*internal.Issue)) error {
+	issue, err := m.Get(number)
+	if err != nil {
+		return err
+	}
+
+	change(&issue)
+	issue.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+
+	if err := os.WriteFile(m.path(number), []byte(formatTaskFile(issue)), markdownFilePerms); err != nil {
+		return fmt.Errorf("failed to write task file: %w", err)
+	}
+	return nil
+}
+
+func (m *Markdown) readAll() ([]internal.Issue, error) {
+	entries, err := os.ReadDir(m.dir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", m.dir, err)
+	}
+
+	var issues []internal.Issue
+	for _, entry := range entries {
+		name, isMarkdown := strings.CutSuffix(entry.Name(), ".md")
+		number, err := strconv.Atoi(name)
+		if entry.IsDir() || !isMarkdown || err != nil {
+			continue // not a task file
+		}
+
+		content, err := os.ReadFile(filepath.Join(m.dir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		issues = append(issues, parseTaskFile(number, string(content)))
+	}
+	return issues, nil
+}
+
+// lastNumber returns the highest task number recorded as used
+func (m *Markdown) lastNumber() int {
+	content, err := os.ReadFile(filepath.Join(m.dir, markdownCounterFile))
+	if err != nil {
+		return 0
+	}
+	number, _ := strconv.Atoi(strings.TrimSpace(string(content)))
+	return number
+}
+
+// recordNumber raises the counter to number
+func (m *Markdown) recordNumber(number int) error {
+	if number <= m.lastNumber() {
+		return nil
+	}
+	path := filepath.Join(m.dir, markdownCounterFile)
+	if err := os.WriteFile(path, []byte(strconv.Itoa(number)+"\n"), markdownFilePerms); err != nil {
+		return fmt.Errorf("failed to record task number: %w", err)
+	}
+	return nil
+}
+
+func (m *Markdown) path(number int) string {
+	return filepath.Join(m.dir, strconv.Itoa(number)+".md")
+}
+
+// parseTaskFile converts a task file into an issue. The priority field is
+// exposed as a P0-P3 label, like GitHub priority labels.
+func parseTaskFile(number int, content string) internal.Issue {
+	fields, body := internal.ParseFrontmatter(content)
+
+	// Comments follow the body, each introduced by a marker line
+	var comments []internal.Comment
+	if markers := markdownCommentRe.FindAllStringSubmatchIndex(body, -1); len(markers) > 0 {
+		for i, marker := range markers {
+			end := len(body)
+			if i+1 < len(markers) {
+				end = markers[i+1][0]
+			}
+			author, _ := strconv.Unquote(body[marker[2]:marker[3]])
+			created, _ := strconv.Unquote(body[marker[4]:marker[5]])
+			comments = append(comments, internal.Comment{
+				Author:    internal.User{Login: author},
+				CreatedAt: created,
+				Body:      strings.TrimSpace(body[marker[1]:end]),
+			})
+		}
+		body = body[:markers[0][0]]
+	}
+
+	issue := internal.Issue{
+		Number:    number,
+		Title:     fields["title"],
+		Body:      strings.TrimSpace(body),
+		State:     internal.StateOpen,
+		Comments:  comments,
+		CreatedAt: fields["created"],
+		UpdatedAt: fields["updated"],
+		ClosedAt:  fields["closed"],
+	}
+	for _, login := range internal.ParseFrontmatterList(fields["assignees"]) {
+		issue.Assignees = append(issue.Assignees, internal.User{Login: login})
+	}
+	if strings.EqualFold(fields["state"], "closed") {
+		issue.State = internal.StateClosed
+	}
+
+	priority := strings.ToUpper(fields["priority"])
+	if priority == "" {
+		priority = markdownDefaultPriority
+	}
+	labels := append([]string{priority}, internal.ParseFrontmatterList(fields["labels"])...)
+	issue.Labels = applyLabels(nil, labels, nil)
+
+	return issue
+}
+
+func formatTaskFile(issue internal.Issue) string {
+	priority := internal.ExtractPriority(issue)
+
+	var labels []string
+	for _, label := range issue.Labels {
+		if label.Name != priority {
+			labels = append(labels, label.Name)
+		}
+	}
+
+	fields := []internal.FrontmatterField{
+		{Key: "title", Value: issue.Title},
+		{Key: "priority", Value: priority},
+		{Key: "state", Value: strings.ToLower(issue.State)},
+		{Key: "labels", Value: internal.FormatFrontmatterList(labels)},
+	}
+	if len(issue.Assignees) > 0 {
+		var logins []string
+		for _, user := range issue.Assignees {
+			logins = append(logins, user.Login)
+		}
+		fields = append(fields, internal.FrontmatterField{Key: "assignees", Value: internal.FormatFrontmatterList(logins)})
+	}
+	fields = append(fields,
+		internal.FrontmatterField{Key: "created", Value: issue.CreatedAt},
+		internal.FrontmatterField{Key: "updated", Value: issue.UpdatedAt})
+	if issue.ClosedAt != "" {
+		fields = append(fields, internal.FrontmatterField{Key: "closed", Value: issue.ClosedAt})
+	}
+
+	body := issue.Body
+	for _, comment := range issue.Comments {
+		body += fmt.Sprintf("\n\n%sauthor=%q created=%q -->\n%s", markdownCommentMarker,
+			comment.Author.Login, comment.CreatedAt, strings.TrimSpace(comment.Body))
+	}
+	return internal.FormatFrontmatter(fields, strings.TrimLeft(body, "\n"))
+}
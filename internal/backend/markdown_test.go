@@ -0,0 +1,87 @@
+package backend
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+func TestTaskFileRoundTrip(t *testing.T) {
+	tests := []struct {
+		name  string
+		issue internal.Issue
+	}{
+		{
+			name: "minimal",
+			issue: internal.Issue{
+				Number: 1, Title: "Fix login", State: internal.StateOpen,
+				Labels:    []internal.Label{{Name: "P2"}},
+				CreatedAt: "2026-01-02T03:04:05Z", UpdatedAt: "2026-01-02T03:04:05Z",
+			},
+		},
+		{
+			name: "closed with labels, assignees and a body",
+			issue: internal.Issue{
+				Number: 7, Title: "#7 looks like a comment: but is a title", State: internal.StateClosed,
+				Body:      "Steps:\n\n1. open\n2. crash",
+				Labels:    []internal.Label{{Name: "P0"}, {Name: "bug"}, {Name: "needs triage"}},
+				Assignees: []internal.User{{Login: "ana"}, {Login: "bo"}},
+				CreatedAt: "2026-01-02T03:04:05Z", UpdatedAt: "2026-01-03T03:04:05Z", ClosedAt: "2026-01-03T03:04:05Z",
+			},
+		},
+		{
+			name: "comments with quoted authors",
+			issue: internal.Issue{
+				Number: 3, Title: "Comments", State: internal.StateOpen, Body: "body",
+				Labels: []internal.Label{{Name: "P1"}},
+				Comments: []internal.Comment{
+					{Author: internal.User{Login: `Ana "the dev" Lima`}, CreatedAt: "2026-01-02T03:04:05Z", Body: "first"},
+					{Author: internal.User{Login: "bo"}, CreatedAt: "2026-01-02T04:04:05Z", Body: "second\n\nparagraph"},
+				},
+				CreatedAt: "2026-01-02T03:04:05Z", UpdatedAt: "2026-01-02T04:04:05Z",
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			content := formatTaskFile(tt.issue)
+			got := parseTaskFile(tt.issue.Number, content)
+			if !reflect.DeepEqual(got, tt.issue) {
+				t.Errorf("round trip of:\n%s\ngot  %+v\nwant %+v", content, got, tt.issue)
+			}
+		})
+	}
+}
+
+func TestMarkdownNumbersAreNotReused(t *testing.T) {
+	m := NewMarkdown(t.TempDir())
+
+	var numbers []int
+	create := func() {
+		issue, err := m.Create("task", "", []string{"P2"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		numbers = append(numbers, issue.Number)
+	}
+
+	create()
+	create()
+	if err := m.Delete(2); err != nil {
+		t.Fatal(err)
+	}
+	create()
+	if err := m.Delete(3); err != nil {
+		t.Fatal(err)
+	}
+	if err := m.Delete(1); err != nil {
+		t.Fatal(err)
+	}
+	create()
+
+	if want := []int{1, 2, 3, 4}; !reflect.DeepEqual(numbers, want) {
+		t.Errorf("numbers = %v, want %v", numbers, want)
+	}
+}
@@ -0,0 +1,89 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+	"github.com/DeprecatedLuar/ghtask/internal/github"
+)
+
+// OpenBackendOrDie returns the backend selected by the "backend" setting
+// (GT_BACKEND or git config ghtask.backend), defaulting to GitHub Issues.
+// Exits with an error message if the backend cannot be opened.
+func OpenBackendOrDie() backend.Backend {
+	switch kind := config.GetOr("backend", backend.KindGitHub); kind {
+	case backend.KindGitHub:
+		return backend.NewGitHub(internal.GetRepoOrDie())
+	case backend.KindGit:
+		b, err := backend.NewGitRef("")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		return b
+	case backend.KindMarkdown:
+		return backend.NewMarkdown("")
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown backend: %s (must be '%s', '%s' or '%s')\n",
+			kind, backend.KindGitHub, backend.KindGit, backend.KindMarkdown)
+		os.Exit(1)
+		return nil
+	}
+}
+
+// openChangeBackendOrDie opens the backend for a command that modifies
+// issues. Changes are recorded for `gt undo`, or with dryRun (--dry-run)
+// printed instead of applied.
+func openChangeBackendOrDie(dryRun bool) backend.Backend {
+	b := OpenBackendOrDie()
+	if dryRun {
+		return backend.NewDryRun(b, os.Stdout)
+	}
+	return withJournal(b)
+}
+
+// issueRef returns the issue URL when the backend has one, or #number otherwise
+func issueRef(issue internal.Issue) string {
+	if issue.URL != "" {
+		return issue.URL
+	}
+	return fmt.Sprintf("#%d", issue.Number)
+}
+
+// repoKey identifies the current repository: its GitHub owner/name, or its
+// location on disk for local backends
+func repoKey() (string, error) {
+	if config.GetOr("backend", backend.KindGitHub) == backend.KindGitHub {
+		if repo, err := github.GetRepoFromGit(); err == nil {
+			return repo, nil
+		}
+	}
+	return gitToplevel()
+}
+
+// repoStatePath returns the file under the data directory's subdir that holds
+// local state for the current repository (e.g. todos/owner_repo.json)
+func repoStatePath(subdir, ext string) (string, error) {
+	key, err := repoKey()
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := config.DataDir(subdir)
+	if err != nil {
+		return "", err
+	}
+
+	name := strings.Trim(strings.Map(func(r rune) rune {
+		if r == '/' || r == '\\' || r == ':' {
+			return '_'
+		}
+		return r
+	}, key), "_")
+	return filepath.Join(dir, name+ext), nil
+}
@@ -0,0 +1,221 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+const (
+	// Branch name used by gt start --branch unless branch-template is set.
+	// Placeholders: {number}, {slug} (from the title) and {priority} (p0-p3)
+	defaultBranchTemplate = "{number}-{slug}"
+
+	// Longest slug taken from an issue title
+	maxSlugLength = 40
+)
+
+// Commands that close or delete the branch's issue only after confirmation
+var destructiveCommands = map[string]bool{"done": true, "rm": true}
+
+var (
+	branchPlaceholderRe = regexp.MustCompile(`\{(number|slug|priority)\}`)
+	slugSeparatorRe     = regexp.MustCompile(`[^a-z0-9]+`)
+)
+
+// branchTemplate returns the branch-template setting
+func branchTemplate() string {
+	return config.GetOr("branch-template", defaultBranchTemplate)
+}
+
+// branchName fills the branch template for issue
+func branchName(template string, issue internal.Issue) string {
+	return branchPlaceholderRe.ReplaceAllStringFunc(template, func(placeholder string) string {
+		switch placeholder {
+		case "{number}":
+			return strconv.Itoa(issue.Number)
+		case "{slug}":
+			return slugify(issue.Title)
+		default:
+			return strings.ToLower(internal.ExtractPriority(issue))
+		}
+	})
+}
+
+// slugify turns a title into a branch-safe slug: "Fix auth bug!" → "fix-auth-bug"
+func slugify(title string) string {
+	slug := strings.Trim(slugSeparatorRe.ReplaceAllString(strings.ToLower(title), "-"), "-")
+	if len(slug) > maxSlugLength {
+		slug = slug[:maxSlugLength]
+		if cut := strings.LastIndex(slug, "-"); cut > 0 {
+			slug = slug[:cut] // don't end on half a word
+		}
+	}
+	return slug
+}
+
+// currentBranchIssue returns the issue number and slug encoded in the
+// current branch name by the branch template, if any. Templates made of
+// nothing but {number} would read any numeric branch, so they name no issue.
+func currentBranchIssue() (int, string, string, bool) {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return 0, "", "", false // not in a repository, or detached HEAD
+	}
+	branch := strings.TrimSpace(string(output))
+
+	// Build a pattern from the template: {number} captures digits, {slug}
+	// captures the slug and {priority} matches anything
+	template := branchTemplate()
+	if !strings.Contains(template, "{number}") || template == "{number}" {
+		return 0, "", "", false
+	}
+	var pattern strings.Builder
+	pattern.WriteString("^")
+	last := 0
+	captured := map[string]bool{}
+	for _, match := range branchPlaceholderRe.FindAllStringSubmatchIndex(template, -1) {
+		pattern.WriteString(regexp.QuoteMeta(template[last:match[0]]))
+		switch name := template[match[2]:match[3]]; {
+		case name == "number" && !captured[name]:
+			pattern.WriteString(`(?P<number>\d+)`)
+		case name == "slug" && !captured[name]:
+			pattern.WriteString(`(?P<slug>.*?)`)
+		case name == "number":
+			pattern.WriteString(`\d+`)
+		default:
+			pattern.WriteString(`.*?`)
+		}
+		captured[template[match[2]:match[3]]] = true
+		last = match[1]
+	}
+	pattern.WriteString(regexp.QuoteMeta(template[last:]) + "$")
+
+	re, err := regexp.Compile(pattern.String())
+	if err != nil {
+		return 0, "", "", false
+	}
+	groups := re.FindStringSubmatch(branch)
+	if groups == nil {
+		return 0, "", "", false
+	}
+	number, err := strconv.Atoi(groups[re.SubexpIndex("number")])
+	if err != nil || number <= 0 {
+		return 0, "", "", false
+	}
+	slug := ""
+	if index := re.SubexpIndex("slug"); index > 0 {
+		slug = groups[index]
+	}
+	return number, slug, branch, true
+}
+
+// branchIssue returns the issue named by the current branch. When the
+// template has a {slug}, it must match the issue's title, so branches like
+// 2024-release or 2-factor-auth are not taken for #2024 or #2.
+func branchIssue(b backend.Backend) (internal.Issue, string, bool) {
+	number, slug, branch, ok := currentBranchIssue()
+	if !ok {
+		return internal.Issue{}, "", false
+	}
+
+	issue, err := b.Get(number)
+	if err != nil {
+		return internal.Issue{}, "", false
+	}
+
+	if strings.Contains(branchTemplate(), "{slug}") {
+		title := slugify(issue.Title)
+		if slug == "" || !strings.HasPrefix(title, slug) && !strings.HasPrefix(slug, title) {
+			return internal.Issue{}, "", false
+		}
+	}
+	return issue, branch, true
+}
+
+// checkoutIssueBranch switches to the issue's branch, creating it from the
+// current HEAD if it does not exist yet
+func checkoutIssueBranch(issue internal.Issue, dryRun bool) error {
+	name := branchName(branchTemplate(), issue)
+	if err := exec.Command("git", "check-ref-format", "--branch", name).Run(); err != nil {
+		return fmt.Errorf("invalid branch name %q (check branch-template)", name)
+	}
+
+	exists := exec.Command("git", "rev-parse", "--verify", "--quiet", "refs/heads/"+name).Run() == nil
+
+	if dryRun {
+		if exists {
+			fmt.Printf("would check out branch %s\n", name)
+		} else {
+			fmt.Printf("would create and check out branch %s\n", name)
+		}
+		return nil
+	}
+
+	args := []string{"checkout", "-b", name}
+	if exists {
+		args = []string{"checkout", name}
+	}
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("git checkout: %s", strings.TrimSpace(string(output)))
+	}
+
+	if exists {
+		fmt.Printf("✓ Switched to branch %s\n", name)
+	} else {
+		fmt.Printf("✓ Switched to new branch %s\n", name)
+	}
+	return nil
+}
+
+// parseBranchFlag extracts --branch/--no-branch from args; without either the
+// branch setting decides
+func parseBranchFlag(args []string) (bool, []string) {
+	branch := config.GetBool("branch")
+	remaining := []string{}
+
+	for _, arg := range args {
+		switch arg {
+		case "--branch":
+			branch = true
+		case "--no-branch":
+			branch = false
+		default:
+			remaining = append(remaining, arg)
+		}
+	}
+
+	return branch, remaining
+}
+
+// branchIssueOrZero returns the issue of the current branch, or 0 when the
+// branch does not name one. Commands that close or delete it ask first, and
+// refuse without a terminal; others report the issue used on stderr.
+func branchIssueOrZero(b backend.Backend, commandName string) int {
+	issue, branch, ok := branchIssue(b)
+	if !ok {
+		return 0
+	}
+
+	if !destructiveCommands[commandName] {
+		fmt.Fprintf(os.Stderr, "Using #%d from branch %s\n", issue.Number, branch)
+		return issue.Number
+	}
+
+	if !isInteractive() {
+		fmt.Fprintf(os.Stderr, "Error: branch %s names #%d; run gt %s %d to confirm\n", branch, issue.Number, commandName, issue.Number)
+		os.Exit(1)
+	}
+	if !confirm(fmt.Sprintf("%s #%d %q (from branch %s)?", commandName, issue.Number, issue.Title, branch)) {
+		fmt.Println("Aborted")
+		os.Exit(1)
+	}
+	return issue.Number
+}
@@ -0,0 +1,126 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+	"sync"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const (
+	// Issues modified at the same time by bulk commands
+	bulkWorkers = 4
+)
+
+// resolveIssueRefsOrDie expands the references of a bulk command into issue
+// numbers. Filter expressions and title fragments select open issues;
+// explicit numbers are kept even when a filter would not select them.
+// Without references the issue named by the current branch is used, or
+// picked interactively.
+func resolveIssueRefsOrDie(b backend.Backend, args []string, commandName string) []int {
+	if len(args) == 0 {
+		if number := branchIssueOrZero(b, commandName); number > 0 {
+			return []int{number}
+		}
+		return []int{pickOpenIssueOrDie(b, commandName)}
+	}
+
+	refs, err := ParseIssueRefs(args, commandName)
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	if len(refs.Filters) == 0 && len(refs.Titles) == 0 {
+		return sortedNumbers(refs.Numbers)
+	}
+
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	return selectIssueRefsOrDie(issues, refs, "open")
+}
+
+// selectIssueRefsOrDie adds the issues selected by the filters and title
+// fragments of refs (among issues, described by kind) to its numbers
+func selectIssueRefsOrDie(issues []internal.Issue, refs IssueRefs, kind string) []int {
+	numbers := refs.Numbers
+
+	for _, title := range refs.Titles {
+		numbers = append(numbers, resolveTitleOrDie(issues, title))
+	}
+
+	if len(refs.Filters) > 0 {
+		matched := filterIssues(issues, refs.Filters)
+		if len(matched) == 0 && len(numbers) == 0 {
+			fmt.Fprintf(os.Stderr, "No %s issues match: %v\n", kind, refs.Filters)
+			os.Exit(1)
+		}
+		for _, issue := range matched {
+			numbers = append(numbers, issue.Number)
+		}
+	}
+
+	return sortedNumbers(numbers)
+}
+
+func sortedNumbers(numbers []int) []int {
+	slices.Sort(numbers)
+	return slices.Compact(numbers)
+}
+
+// runBulk applies action to every issue with a bounded worker pool, printing
+// one line per issue as it finishes. action returns the success message, or
+// "" for none (dry runs, where the backend prints the plan instead); failures
+// are reported as "Error <verb> #N". With more than one issue a summary
+// follows, and any failure makes gt exit non-zero.
+func runBulk(numbers []int, verb string, action func(number int) (string, error)) {
+	if runBulkFailed(numbers, verb, action) > 0 {
+		os.Exit(1)
+	}
+}
+
+// runBulkFailed runs action like runBulk and returns the number of failures
+// instead of exiting, for callers with something to keep first
+func runBulkFailed(numbers []int, verb string, action func(number int) (string, error)) int {
+	jobs := make(chan int)
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	failed := 0
+
+	workers := min(bulkWorkers, len(numbers))
+	for range workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for number := range jobs {
+				message, err := action(number)
+
+				mu.Lock()
+				if err != nil {
+					fmt.Fprintf(os.Stderr, "✗ Error %s #%d: %v\n", verb, number, err)
+					failed++
+				} else if message != "" {
+					fmt.Printf("✓ %s\n", message)
+				}
+				mu.Unlock()
+			}
+		}()
+	}
+
+	for _, number := range numbers {
+		jobs <- number
+	}
+	close(jobs)
+	wg.Wait()
+
+	if len(numbers) > 1 {
+		fmt.Printf("\n%d succeeded, %d failed\n", len(numbers)-failed, failed)
+	}
+	return failed
+}
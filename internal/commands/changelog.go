@@ -0,0 +1,315 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"regexp"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const (
+	groupPriority = "priority"
+	groupLabel    = "label"
+
+	formatMarkdown        = "markdown"
+	formatKeepAChangelog  = "keepachangelog"
+	unreleasedVersion     = "Unreleased"
+	otherChangelogSection = "Other"
+)
+
+// Headings of priority groups
+var priorityHeadings = map[string]string{
+	"P0": "Critical",
+	"P1": "Important",
+	"P2": "Normal",
+	"P3": "Low priority",
+}
+
+// Keep a Changelog sections, in the order the format lists them
+var keepAChangelogSections = []string{"Added", "Changed", "Deprecated", "Removed", "Fixed", "Security"}
+
+// Labels that track workflow rather than the kind of change
+var workflowLabels = []string{"inbox", "active", "review", trashLabel}
+
+// Issue references in commit messages: #12, but not &#12; or abc#12
+var commitIssueRefRe = regexp.MustCompile(`(?:^|[^\w&])#(\d+)\b`)
+
+// Changelog prints the issues closed between two git revisions (usually
+// tags): those closed after the first and up to the second, plus closed issues
+// referenced by commits in the range. Without a range it covers the latest
+// tag up to HEAD.
+func Changelog(args []string) {
+	group := groupPriority
+	format := formatMarkdown
+	rangeArg := ""
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case (args[i] == "--group" || args[i] == "-g") && i+1 < len(args):
+			group = args[i+1]
+			i++
+		case (args[i] == "--format" || args[i] == "-f") && i+1 < len(args):
+			format = args[i+1]
+			i++
+		case !strings.HasPrefix(args[i], "-") && rangeArg == "":
+			rangeArg = args[i]
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown argument: %s\n", args[i])
+			fmt.Fprintln(os.Stderr, "Usage: gt changelog [<from>..<to>] [--group priority|label] [--format markdown|keepachangelog]")
+			os.Exit(1)
+		}
+	}
+
+	if group != groupPriority && group != groupLabel {
+		fmt.Fprintf(os.Stderr, "Error: unknown group: %s (must be priority or label)\n", group)
+		os.Exit(1)
+	}
+	if format != formatMarkdown && format != formatKeepAChangelog {
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s (must be markdown or keepachangelog)\n", format)
+		os.Exit(1)
+	}
+
+	from, to, err := parseChangelogRange(rangeArg)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	since, until, err := changelogWindow(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	referenced, err := commitIssueRefs(from, to)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	b := OpenBackendOrDie()
+	closed, err := b.List(backend.ListClosed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	var issues []internal.Issue
+	for _, issue := range closed {
+		if hasLabel(issue, trashLabel) {
+			continue
+		}
+		closedAt, err := time.Parse(time.RFC3339, issue.ClosedAt)
+		if err != nil || !closedAt.After(since) {
+			continue // closed before the previous release
+		}
+		if !closedAt.After(until) || referenced[issue.Number] {
+			issues = append(issues, issue)
+		}
+	}
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Number < issues[j].Number
+	})
+
+	version := to
+	if to == "HEAD" {
+		version = unreleasedVersion
+	}
+	date := until.Local().Format("2006-01-02")
+
+	if format == formatKeepAChangelog {
+		fmt.Print(renderKeepAChangelog(issues, version, date))
+	} else {
+		fmt.Print(renderMarkdownChangelog(issues, group, version, date))
+	}
+}
+
+// parseChangelogRange splits "from..to"; a missing end is HEAD and a missing
+// start the latest tag before the end ("" when there is none)
+func parseChangelogRange(rangeArg string) (string, string, error) {
+	from, to, isRange := strings.Cut(rangeArg, "..")
+	if !isRange {
+		from, to = "", rangeArg
+	}
+	if to == "" {
+		to = "HEAD"
+	}
+	if !isRange {
+		// Latest tag strictly before to, so `gt changelog v1.3.0` covers v1.3.0
+		output, err := exec.Command("git", "describe", "--tags", "--abbrev=0", to+"^").Output()
+		if err == nil {
+			from = strings.TrimSpace(string(output))
+		}
+	}
+
+	for _, rev := range []string{from, to} {
+		if rev == "" {
+			continue
+		}
+		if err := exec.Command("git", "rev-parse", "--verify", "--quiet", rev+"^{commit}").Run(); err != nil {
+			return "", "", fmt.Errorf("unknown revision: %s", rev)
+		}
+	}
+	return from, to, nil
+}
+
+// changelogWindow returns the commit dates of from and to; without from the
+// window starts at the beginning of time. The end is now when to is HEAD, so
+// issues closed after the last commit count as unreleased.
+func changelogWindow(from, to string) (time.Time, time.Time, error) {
+	var since time.Time
+	if from != "" {
+		var err error
+		if since, err = commitDate(from); err != nil {
+			return time.Time{}, time.Time{}, err
+		}
+	}
+
+	if to == "HEAD" {
+		return since, time.Now(), nil
+	}
+	until, err := commitDate(to)
+	return since, until, err
+}
+
+func commitDate(rev string) (time.Time, error) {
+	output, err := exec.Command("git", "log", "-1", "--format=%cI", rev).Output()
+	if err != nil {
+		return time.Time{}, fmt.Errorf("failed to read the date of %s", rev)
+	}
+	return time.Parse(time.RFC3339, strings.TrimSpace(string(output)))
+}
+
+// commitIssueRefs returns the issues referenced by commit messages in from..to
+func commitIssueRefs(from, to string) (map[int]bool, error) {
+	revs := to
+	if from != "" {
+		revs = from + ".." + to
+	}
+	output, err := exec.Command("git", "log", "--format=%B", revs).Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read commits in %s", revs)
+	}
+
+	refs := map[int]bool{}
+	for _, match := range commitIssueRefRe.FindAllStringSubmatch(string(output), -1) {
+		if number, err := strconv.Atoi(match[1]); err == nil {
+			refs[number] = true
+		}
+	}
+	return refs, nil
+}
+
+// renderMarkdownChangelog groups issues under priority or label headings
+func renderMarkdownChangelog(issues []internal.Issue, group, version, date string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "## %s (%s)\n", version, date)
+	if len(issues) == 0 {
+		sb.WriteString("\nNo closed issues.\n")
+		return sb.String()
+	}
+
+	var sections []string
+	grouped := map[string][]internal.Issue{}
+	for _, issue := range issues {
+		var section string
+		if group == groupPriority {
+			section = internal.ExtractPriority(issue)
+		} else {
+			section = typeLabel(issue)
+		}
+		if _, ok := grouped[section]; !ok {
+			sections = append(sections, section)
+		}
+		grouped[section] = append(grouped[section], issue)
+	}
+
+	// Priorities sort as P0-P3; labels alphabetically with Other last
+	sort.Slice(sections, func(i, j int) bool {
+		if (sections[i] == otherChangelogSection) != (sections[j] == otherChangelogSection) {
+			return sections[j] == otherChangelogSection
+		}
+		return sections[i] < sections[j]
+	})
+
+	for _, section := range sections {
+		heading := section
+		if group == groupPriority {
+			heading = fmt.Sprintf("%s (%s)", priorityHeadings[section], section)
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", heading)
+		writeChangelogEntries(&sb, grouped[section])
+	}
+	return sb.String()
+}
+
+// renderKeepAChangelog sorts issues into the Keep a Changelog sections by
+// their labels (https://keepachangelog.com)
+func renderKeepAChangelog(issues []internal.Issue, version, date string) string {
+	var sb strings.Builder
+	if version == unreleasedVersion {
+		fmt.Fprintf(&sb, "## [%s]\n", version)
+	} else {
+		fmt.Fprintf(&sb, "## [%s] - %s\n", strings.TrimPrefix(version, "v"), date)
+	}
+
+	grouped := map[string][]internal.Issue{}
+	for _, issue := range issues {
+		section := keepAChangelogSection(issue)
+		grouped[section] = append(grouped[section], issue)
+	}
+
+	for _, section := range keepAChangelogSections {
+		if len(grouped[section]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&sb, "\n### %s\n\n", section)
+		writeChangelogEntries(&sb, grouped[section])
+	}
+	return sb.String()
+}
+
+func writeChangelogEntries(sb *strings.Builder, issues []internal.Issue) {
+	for _, issue := range issues {
+		fmt.Fprintf(sb, "- %s (#%d)\n", issue.Title, issue.Number)
+	}
+}
+
+// typeLabel returns the first label describing the kind of change, ignoring
+// priorities and workflow labels
+func typeLabel(issue internal.Issue) string {
+	for _, label := range issue.Labels {
+		if !isPriority(label.Name) && !slices.Contains(workflowLabels, label.Name) {
+			return label.Name
+		}
+	}
+	return otherChangelogSection
+}
+
+// keepAChangelogSection maps the usual issue labels (bug, enhancement,
+// security, ...) to a Keep a Changelog section; anything else is Changed
+func keepAChangelogSection(issue internal.Issue) string {
+	for _, label := range issue.Labels {
+		name := strings.ToLower(label.Name)
+		switch {
+		case strings.Contains(name, "security"):
+			return "Security"
+		case strings.Contains(name, "bug") || strings.Contains(name, "fix"):
+			return "Fixed"
+		case strings.Contains(name, "deprecat"):
+			return "Deprecated"
+		case strings.Contains(name, "remov"):
+			return "Removed"
+		case strings.Contains(name, "enhancement") || strings.Contains(name, "feature"):
+			return "Added"
+		}
+	}
+	return "Changed"
+}
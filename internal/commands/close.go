@@ -1,50 +1,25 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"os"
-	"os/exec"
-
-	"github.com/DeprecatedLuar/ghtask/internal"
 )
 
 func CloseIssue(args []string) {
-	issueNum, err := ParseIssueNumber(args, "done")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
-	}
-
-	repo := internal.GetRepoOrDie()
-
-	cmd := exec.Command("gh", "issue", "view", issueNum,
-		"--repo", repo,
-		"--json", "title")
-
-	output, err := cmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
-		os.Exit(1)
-	}
-
-	var issueData struct {
-		Title string `json:"title"`
-	}
-	if err := json.Unmarshal(output, &issueData); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing issue: %v\n", err)
-		os.Exit(1)
-	}
-
-	cmd = exec.Command("gh", "issue", "close", issueNum,
-		"--repo", repo)
-
-	output, err = cmd.CombinedOutput()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error closing issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "%s\n", output)
-		os.Exit(1)
-	}
-
-	fmt.Printf("✓ Closed #%s: %s\n", issueNum, issueData.Title)
+	dryRun, args := ParseDryRunFlag(args)
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args, "done")
+
+	runBulk(numbers, "closing", func(number int) (string, error) {
+		issue, err := b.Get(number)
+		if err != nil {
+			return "", err
+		}
+		if err := b.Close(number); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("Closed #%d: %s", number, issue.Title), nil
+	})
 }
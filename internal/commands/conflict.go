@@ -0,0 +1,124 @@
+package commands
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+const (
+	// Longest conflict count git merge-file reports; higher exit codes are errors
+	maxMergeConflicts = 127
+
+	recoveryFilePerms = 0600
+)
+
+// mergeEdit reconciles an edit with changes made to the issue while the
+// editor was open. base is the text the editor started from, mine the edited
+// text and theirs the issue's current text. Without a concurrent change mine
+// is returned as is; otherwise the user chooses between a three-way merge,
+// overwriting, or keeping their version for later (which exits): in the draft
+// d when the edit came from one, or in a recovery file. Without a terminal the
+// edit is kept and gt exits.
+func mergeEdit(number int, what, base, mine, theirs string, d *draft) string {
+	if theirs == base || theirs == mine {
+		return mine
+	}
+
+	fmt.Fprintf(os.Stderr, "#%d changed (%s) while you were editing.\n", number, what)
+	if !isInteractive() {
+		saveRecoveryOrDie(number, mine, d)
+	}
+
+	for {
+		switch strings.ToLower(ask("[m]erge, [o]verwrite their changes, or [s]ave yours for later and quit? ")) {
+		case "m", "merge":
+			merged, conflicts, err := mergeThreeWay(base, mine, theirs)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error merging: %v\n", err)
+				saveRecoveryOrDie(number, mine, d)
+			}
+			if conflicts == 0 {
+				fmt.Println("✓ Merged with their changes")
+				return merged
+			}
+
+			fmt.Printf("%s; resolve the <<<<<<< markers in the editor\n", plural(conflicts, "conflict"))
+			resolved, err := internal.OpenEditorWithContent(merged, "merge")
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error opening editor: %v\n", err)
+				saveRecoveryOrDie(number, mine, d)
+			}
+			if strings.Contains(resolved, "<<<<<<<") || strings.Contains(resolved, ">>>>>>>") {
+				fmt.Fprintln(os.Stderr, "Error: conflict markers left in the text")
+				saveRecoveryOrDie(number, mine, d)
+			}
+			return resolved
+		case "o", "overwrite":
+			return mine
+		case "s", "save", "q", "":
+			saveRecoveryOrDie(number, mine, d)
+		}
+	}
+}
+
+// mergeThreeWay merges the changes from base to mine and from base to theirs
+// with git merge-file, returning the result (with conflict markers) and the
+// number of conflicts
+func mergeThreeWay(base, mine, theirs string) (string, int, error) {
+	dir, err := os.MkdirTemp("", "ghtask-merge-")
+	if err != nil {
+		return "", 0, err
+	}
+	defer os.RemoveAll(dir)
+
+	paths := map[string]string{"base": base, "mine": mine, "theirs": theirs}
+	for name, text := range paths {
+		if err := os.WriteFile(filepath.Join(dir, name), []byte(text+"\n"), recoveryFilePerms); err != nil {
+			return "", 0, err
+		}
+	}
+
+	cmd := exec.Command("git", "merge-file", "-p",
+		"-L", "yours", "-L", "original", "-L", "theirs",
+		filepath.Join(dir, "mine"), filepath.Join(dir, "base"), filepath.Join(dir, "theirs"))
+	output, err := cmd.Output()
+
+	// The exit code is the number of conflicts
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() > 0 && exitErr.ExitCode() <= maxMergeConflicts {
+		return strings.TrimSpace(string(output)), exitErr.ExitCode(), nil
+	}
+	if err != nil {
+		return "", 0, fmt.Errorf("git merge-file: %w", err)
+	}
+	return strings.TrimSpace(string(output)), 0, nil
+}
+
+// saveRecoveryOrDie keeps text and exits. The draft the edit came from
+// already holds it; otherwise it is written to the recovery directory.
+func saveRecoveryOrDie(number int, text string, d *draft) {
+	if d != nil {
+		d.hint()
+		os.Exit(1)
+	}
+
+	dir, err := config.DataDir("recovery")
+	if err == nil {
+		path := filepath.Join(dir, fmt.Sprintf("issue-%d-%s.md", number, time.Now().Format("20060102-150405")))
+		if err = os.WriteFile(path, []byte(text+"\n"), recoveryFilePerms); err == nil {
+			fmt.Fprintf(os.Stderr, "Your version was saved to %s\n", path)
+			os.Exit(1)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Error saving your version: %v\n", err)
+	fmt.Fprintln(os.Stderr, text)
+	os.Exit(1)
+}
@@ -3,44 +3,101 @@ package commands
 import (
 	"fmt"
 	"os"
-	"os/exec"
 	"strings"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 )
 
-func CreateIssue(args []string, cmd string, hasBody bool, bodyValue string) {
+// CreateIssue creates an issue titled by args. With templateName the body
+// starts from that issue template (opened in the editor when interactive)
+// and the template's labels and assignees are applied.
+func CreateIssue(args []string, cmd string, hasBody bool, bodyValue string, templateName string, dryRun bool) {
 	priority := ParsePriorityFromCommand(cmd)
 
 	if len(args) == 0 {
 		fmt.Fprintln(os.Stderr, "Error: issue title required")
-		fmt.Fprintf(os.Stderr, "Usage: %s <title> [--body [inline-text]]\n", cmd)
+		fmt.Fprintf(os.Stderr, "Usage: %s <title> [--template <name>] [--body [inline-text]]\n", cmd)
 		os.Exit(1)
 	}
 
-	repo := internal.GetRepoOrDie()
+	var template issueTemplate
+	if templateName != "" {
+		template = findTemplateOrDie(templateName)
+	}
+
+	b := openChangeBackendOrDie(dryRun)
+
+	typed := strings.Join(args, " ")
+	title := typed
+	labels := []string{"inbox", priority}
 
-	title := strings.Join(args, " ")
-	labels := "inbox," + priority
+	// Template title prefixes like "[Bug] " are added unless already typed,
+	// with a space after them (frontmatter values lose their trailing blanks)
+	if prefix := expandTemplate(template.prefix, typed, priority); prefix != "" &&
+		!strings.HasPrefix(strings.ToLower(title), strings.ToLower(strings.TrimSpace(prefix))) {
+		if !strings.HasSuffix(prefix, " ") {
+			prefix += " "
+		}
+		title = prefix + title
+	}
+
+	if len(template.labels) > 0 {
+		ensureLabels(b, template.labels)
+		labels = append(labels, template.labels...)
+	}
 
-	body, err := GetContentFromInput(hasBody, bodyValue, "body")
+	// Stdin and inline bodies win over the editor and the template
+	body, err := GetContentFromInput(false, bodyValue, "body")
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error getting body: %v\n", err)
 		os.Exit(1)
 	}
 
-	ghCmd := exec.Command("gh", "issue", "create",
-		"--repo", repo,
-		"--title", title,
-		"--label", labels,
-		"--body", body)
+	// Otherwise --body or a template opens the editor, which starts from the
+	// template; without a terminal the template is used as is. A dry run
+	// saves nothing, so it keeps no draft either.
+	var d *draft
+	if body == "" {
+		body = expandTemplate(template.body, typed, priority)
+		bodyRequested := hasBody && template.body == ""
+		templateToEdit := template.body != "" && isInteractive()
+		if bodyRequested || templateToEdit {
+			if dryRun {
+				body, err = internal.OpenEditorWithContent(body, "body")
+			} else {
+				d = &draft{Kind: draftCreate, Title: title, Labels: labels, Assignees: template.assignees}
+				body, err = editDraft(d, body)
+			}
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error getting body: %v\n", err)
+				os.Exit(1)
+			}
+		}
+	}
+
+	createIssue(b, title, body, labels, template.assignees, d, dryRun)
+}
 
-	output, err := ghCmd.CombinedOutput()
+// createIssue creates the issue, discarding the draft its body came from once
+// it is saved
+func createIssue(b backend.Backend, title, body string, labels, assignees []string, d *draft, dryRun bool) {
+	issue, err := b.Create(title, body, labels)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error creating issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "%s\n", output)
+		d.hint()
 		os.Exit(1)
 	}
+	d.discard()
+
+	if len(assignees) > 0 {
+		if err := b.Update(issue.Number, backend.Update{AddAssignees: assignees}); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to assign %s: %v\n", strings.Join(assignees, ", "), err)
+		}
+	}
+	if dryRun {
+		return
+	}
 
-	fmt.Printf("Created: %s\n", strings.TrimSpace(string(output)))
+	fmt.Printf("Created: %s\n", issueRef(issue))
 }
@@ -1,51 +1,133 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
+	"time"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
 )
 
+// DeleteIssue deletes issues permanently after confirmation (--force skips
+// it). With the trash setting (GT_TRASH=1 or git config ghtask.trash true)
+// issues are moved to the trash instead, see TrashCommand.
 func DeleteIssue(args []string) {
-	issueNum, err := ParseIssueNumber(args, "rm")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
+	dryRun, args := ParseDryRunFlag(args)
+	force, args := parseForceFlag(args)
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args, "rm")
+
+	if config.GetBool("trash") {
+		trashIssues(b, numbers, dryRun)
+		return
 	}
 
-	repo := internal.GetRepoOrDie()
+	if !force && !dryRun {
+		confirmDeleteOrDie(b, numbers)
+	}
+	deleteIssues(b, numbers, dryRun)
+}
 
-	cmd := exec.Command("gh", "issue", "view", issueNum,
-		"--repo", repo,
-		"--json", "title")
+func deleteIssues(b backend.Backend, numbers []int, dryRun bool) {
+	runBulk(numbers, "deleting", func(number int) (string, error) {
+		issue, err := b.Get(number)
+		if err != nil {
+			return "", err
+		}
+		if err := b.Delete(number); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("Deleted #%d: %s", number, issue.Title), nil
+	})
+}
 
-	output, err := cmd.Output()
+// confirmDeleteOrDie lists the issues about to be deleted with their age and
+// exits unless the user agrees. Without a terminal there is nobody to ask,
+// so --force is required.
+func confirmDeleteOrDie(b backend.Backend, numbers []int) {
+	if !isInteractive() {
+		fmt.Fprintln(os.Stderr, "Error: refusing to delete without confirmation (not a terminal); use --force")
+		os.Exit(1)
+	}
+
+	issues, err := b.List(backend.ListAll)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
 		os.Exit(1)
 	}
+	byNumber := map[int]internal.Issue{}
+	for _, issue := range issues {
+		byNumber[issue.Number] = issue
+	}
+
+	fmt.Println("About to delete permanently:")
+	for _, number := range numbers {
+		issue, ok := byNumber[number]
+		if !ok {
+			fmt.Printf("  #%d  (not found)\n", number)
+			continue
+		}
+		fmt.Printf("  #%d  %s  (opened %s)\n", number, issue.Title, formatAge(issue.CreatedAt))
+	}
 
-	var issueData struct {
-		Title string `json:"title"`
+	question := "Delete this issue?"
+	if len(numbers) > 1 {
+		question = fmt.Sprintf("Delete these %d issues?", len(numbers))
 	}
-	if err := json.Unmarshal(output, &issueData); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing issue: %v\n", err)
+	if !confirm(question) {
+		fmt.Println("Aborted")
 		os.Exit(1)
 	}
+}
+
+// parseForceFlag extracts --force/-f from args
+func parseForceFlag(args []string) (bool, []string) {
+	force := false
+	remaining := []string{}
+
+	for _, arg := range args {
+		if arg == "--force" || arg == "-f" {
+			force = true
+		} else {
+			remaining = append(remaining, arg)
+		}
+	}
 
-	cmd = exec.Command("gh", "issue", "delete", issueNum,
-		"--repo", repo,
-		"--yes")
+	return force, remaining
+}
 
-	output, err = cmd.CombinedOutput()
+// formatAge describes how long ago an RFC 3339 timestamp was ("3 days ago")
+func formatAge(timestamp string) string {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error deleting issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "%s\n", output)
-		os.Exit(1)
+		return "at an unknown time"
 	}
 
-	fmt.Printf("✓ Deleted #%s: %s\n", issueNum, issueData.Title)
+	age := time.Since(parsed)
+	switch {
+	case age < time.Minute:
+		return "just now"
+	case age < time.Hour:
+		return plural(int(age.Minutes()), "minute") + " ago"
+	case age < 24*time.Hour:
+		return plural(int(age.Hours()), "hour") + " ago"
+	case age < 60*24*time.Hour:
+		return plural(int(age.Hours()/24), "day") + " ago"
+	case age < 2*365*24*time.Hour:
+		return plural(int(age.Hours()/24/30), "month") + " ago"
+	default:
+		return plural(int(age.Hours()/24/365), "year") + " ago"
+	}
+}
+
+func plural(count int, unit string) string {
+	if count == 1 {
+		return "1 " + unit
+	}
+	return fmt.Sprintf("%d %ss", count, unit)
 }
@@ -0,0 +1,362 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+// What a draft was written for
+const (
+	draftCreate = "create"
+	draftEdit   = "edit"
+	draftPlan   = "plan"
+)
+
+const (
+	draftFilePerms = 0600
+
+	// Characters of a draft's text shown by gt drafts list
+	draftPreviewLength = 50
+)
+
+// draft is editor content kept in the data directory until the backend has
+// accepted it, so a failed create or update does not lose it. The text lives
+// in <id>.md, what to do with it in <id>.json.
+type draft struct {
+	id        int
+	Kind      string   `json:"kind"`
+	Repo      string   `json:"repo,omitempty"`
+	Number    int      `json:"number,omitempty"`
+	Field     string   `json:"field,omitempty"` // title, body, or "issue" for the whole document
+	Title     string   `json:"title,omitempty"` // of the issue to create
+	Labels    []string `json:"labels,omitempty"`
+	Assignees []string `json:"assignees,omitempty"`
+	Base      string   `json:"base,omitempty"` // text the edit started from
+	UpdatedAt string   `json:"updatedAt,omitempty"`
+	Created   string   `json:"created"`
+}
+
+// DraftsCommand manages the drafts left by failed creates and edits:
+// gt drafts [list], gt drafts resume [id], gt drafts discard <ids...|--all>
+func DraftsCommand(args []string) {
+	sub := "list"
+	if len(args) > 0 {
+		sub, args = args[0], args[1:]
+	}
+
+	switch sub {
+	case "list", "ls":
+		listDrafts()
+	case "resume":
+		dryRun, args := ParseDryRunFlag(args)
+		resumeDraft(args, dryRun)
+	case "discard", "rm":
+		discardDrafts(args)
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown drafts command: %s\n", sub)
+		fmt.Fprintln(os.Stderr, "Usage: gt drafts [list | resume [id] | discard <ids...|--all>]")
+		os.Exit(1)
+	}
+}
+
+// editDraft saves initial as a new draft and opens it in the editor. The
+// draft stays on disk until discard is called.
+func editDraft(d *draft, initial string) (string, error) {
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		return "", err
+	}
+
+	d.id = nextDraftID(dir)
+	d.Created = time.Now().UTC().Format(time.RFC3339)
+	if d.Repo == "" {
+		d.Repo, _ = repoKey()
+	}
+
+	meta, err := json.MarshalIndent(d, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(d.metaPath(dir), meta, draftFilePerms); err != nil {
+		return "", fmt.Errorf("failed to save draft: %w", err)
+	}
+	if err := d.save(initial); err != nil {
+		return "", err
+	}
+
+	return d.edit(dir)
+}
+
+// save replaces the draft's text, as when part of it has been applied
+func (d *draft) save(text string) error {
+	if d == nil {
+		return nil
+	}
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(d.textPath(dir), []byte(text), draftFilePerms); err != nil {
+		return fmt.Errorf("failed to save draft: %w", err)
+	}
+	return nil
+}
+
+// edit opens the draft's text in the editor and returns it
+func (d *draft) edit(dir string) (string, error) {
+	if err := internal.EditFile(d.textPath(dir), d.editorKind()); err != nil {
+		d.hint()
+		return "", err
+	}
+	text, err := os.ReadFile(d.textPath(dir))
+	if err != nil {
+		return "", fmt.Errorf("failed to read draft: %w", err)
+	}
+	return strings.TrimSpace(string(text)), nil
+}
+
+// discard deletes the draft once its text is saved (or no longer wanted)
+func (d *draft) discard() {
+	if d == nil {
+		return
+	}
+	if dir, err := config.DataDir("drafts"); err == nil {
+		os.Remove(d.textPath(dir))
+		os.Remove(d.metaPath(dir))
+	}
+}
+
+// hint tells the user how to recover the draft after a failure
+func (d *draft) hint() {
+	if d != nil {
+		fmt.Fprintf(os.Stderr, "Your text was kept as draft %d: gt drafts resume %d\n", d.id, d.id)
+	}
+}
+
+func (d *draft) textPath(dir string) string {
+	return filepath.Join(dir, strconv.Itoa(d.id)+".md")
+}
+
+func (d *draft) metaPath(dir string) string {
+	return filepath.Join(dir, strconv.Itoa(d.id)+".json")
+}
+
+// editorKind is the kind of text for the editor settings: body, title, issue
+// or plan
+func (d *draft) editorKind() string {
+	switch d.Kind {
+	case draftCreate:
+		return "body"
+	case draftPlan:
+		return "plan"
+	}
+	return d.Field
+}
+
+// describe says what the draft is for: `new issue "title"`, `#12 body` or `plan`
+func (d *draft) describe() string {
+	switch d.Kind {
+	case draftCreate:
+		return fmt.Sprintf("new issue %q", d.Title)
+	case draftPlan:
+		return "plan"
+	}
+	return fmt.Sprintf("#%d %s", d.Number, d.Field)
+}
+
+func nextDraftID(dir string) int {
+	id := 1
+	for _, d := range readDrafts(dir) {
+		id = max(id, d.id+1)
+	}
+	return id
+}
+
+// readDrafts returns the drafts in dir, oldest first
+func readDrafts(dir string) []*draft {
+	paths, _ := filepath.Glob(filepath.Join(dir, "*.json"))
+
+	var drafts []*draft
+	for _, path := range paths {
+		id, err := strconv.Atoi(strings.TrimSuffix(filepath.Base(path), ".json"))
+		if err != nil {
+			continue
+		}
+		content, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		d := &draft{id: id}
+		if json.Unmarshal(content, d) == nil {
+			drafts = append(drafts, d)
+		}
+	}
+
+	sort.Slice(drafts, func(i, j int) bool {
+		return drafts[i].id < drafts[j].id
+	})
+	return drafts
+}
+
+func listDrafts() {
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	drafts := readDrafts(dir)
+	if len(drafts) == 0 {
+		fmt.Println("No drafts")
+		return
+	}
+
+	repo, _ := repoKey()
+	for _, d := range drafts {
+		text, _ := os.ReadFile(d.textPath(dir))
+		preview, _, _ := strings.Cut(strings.TrimSpace(string(text)), "\n")
+		if d.Kind == draftPlan {
+			preview = ""
+			for _, line := range strings.Split(string(text), "\n") {
+				if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+					preview = line // skip the instructions
+					break
+				}
+			}
+		}
+		preview = truncateTitle(preview, draftPreviewLength)
+
+		where := ""
+		if d.Repo != repo {
+			where = " in " + d.Repo
+		}
+		fmt.Printf("%3d  %s%s  (%s)\n", d.id, d.describe(), where, formatAge(d.Created))
+		if preview != "" {
+			fmt.Printf("     %s\n", preview)
+		}
+	}
+}
+
+// resumeDraft reopens a draft (the latest of this repository by default) in
+// the editor and retries what it was written for
+func resumeDraft(args []string, dryRun bool) {
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	d := findDraftOrDie(dir, args)
+	if repo, _ := repoKey(); d.Repo != repo {
+		fmt.Fprintf(os.Stderr, "Error: draft %d belongs to %s; resume it there\n", d.id, d.Repo)
+		os.Exit(1)
+	}
+
+	text, err := d.edit(dir)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error opening editor: %v\n", err)
+		os.Exit(1)
+	}
+
+	meta := *d
+	b := openChangeBackendOrDie(dryRun)
+	if dryRun {
+		d = nil // keep the draft for the real run
+	}
+	switch {
+	case meta.Kind == draftCreate:
+		createIssue(b, meta.Title, text, meta.Labels, meta.Assignees, d, dryRun)
+	case meta.Kind == draftPlan:
+		applyPlanText(b, listPlanIssuesOrDie(b), text, d, false, dryRun)
+	default:
+		base, err := meta.baseIssue()
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: draft %d has an unreadable original: %v\n", meta.id, err)
+			os.Exit(1)
+		}
+		if meta.Field == "issue" {
+			applyDocumentEdit(b, base, meta.Base, text, d, dryRun)
+		} else {
+			applyFieldEdit(b, base, meta.Field, meta.Base, text, d, dryRun)
+		}
+	}
+}
+
+// baseIssue rebuilds the issue an edit draft started from. A document edit
+// keeps every field in its original document, so an issue that cannot be
+// refetched is still diffed against those rather than against nothing.
+func (d *draft) baseIssue() (internal.Issue, error) {
+	base := internal.Issue{}
+	if d.Field == "issue" {
+		var err error
+		if base, err = parseIssueDocument(d.Base); err != nil {
+			return internal.Issue{}, err
+		}
+	}
+	base.Number, base.UpdatedAt = d.Number, d.UpdatedAt
+	return base, nil
+}
+
+func findDraftOrDie(dir string, args []string) *draft {
+	drafts := readDrafts(dir)
+
+	if len(args) == 0 {
+		repo, _ := repoKey()
+		for i := len(drafts) - 1; i >= 0; i-- {
+			if drafts[i].Repo == repo {
+				return drafts[i]
+			}
+		}
+		fmt.Fprintln(os.Stderr, "No drafts for this repository (gt drafts lists all)")
+		os.Exit(1)
+	}
+
+	id, err := strconv.Atoi(args[0])
+	if err == nil {
+		for _, d := range drafts {
+			if d.id == id {
+				return d
+			}
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Error: no draft %s (gt drafts lists them)\n", args[0])
+	os.Exit(1)
+	return nil
+}
+
+func discardDrafts(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: draft id required")
+		fmt.Fprintln(os.Stderr, "Usage: gt drafts discard <ids...|--all>")
+		os.Exit(1)
+	}
+
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	var drafts []*draft
+	if args[0] == "--all" {
+		drafts = readDrafts(dir)
+	} else {
+		for _, arg := range args {
+			drafts = append(drafts, findDraftOrDie(dir, []string{arg}))
+		}
+	}
+
+	for _, d := range drafts {
+		d.discard()
+		fmt.Printf("✓ Discarded draft %d: %s\n", d.id, d.describe())
+	}
+}
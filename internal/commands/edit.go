@@ -1,16 +1,19 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
+	"slices"
 	"strings"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 )
 
+// EditIssue edits the title or body of an issue, or without a field the whole
+// issue as one document (see editIssueDocument)
 func EditIssue(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
 	hasEdit, field, inlineValue, remainingArgs, err := ParseEditFlag(args)
 	if err != nil {
 		fmt.Fprintln(os.Stderr, err)
@@ -19,24 +22,16 @@ func EditIssue(args []string) {
 
 	if !hasEdit {
 		fmt.Fprintln(os.Stderr, "Error: -e flag required")
-		fmt.Fprintln(os.Stderr, "Usage: gt <issue-number> -e <body|title> [inline-value]")
+		fmt.Fprintln(os.Stderr, "Usage: gt <issue-number> -e [body|title] [inline-value]")
 		os.Exit(1)
 	}
 
-	issueNum, err := ParseIssueNumber(remainingArgs, "edit")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
-		os.Exit(1)
-	}
+	b := openChangeBackendOrDie(dryRun)
+	issueNum := resolveIssueRefOrDie(b, remainingArgs, "edit")
 
-	repo := internal.GetRepoOrDie()
-
-	var updateFlag string
-	switch field {
-	case "body":
-		updateFlag = "--body"
-	case "title":
-		updateFlag = "--title"
+	if field == "" {
+		editIssueDocument(b, issueNum, dryRun)
+		return
 	}
 
 	var newContent string
@@ -50,54 +45,351 @@ func EditIssue(args []string) {
 			os.Exit(1)
 		}
 	} else {
-		cmd := exec.Command("gh", "issue", "view", issueNum,
-			"--repo", repo,
-			"--json", "title,body")
-
-		output, err := cmd.Output()
+		issue, err := b.Get(issueNum)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error fetching issue: %v\n", err)
 			os.Exit(1)
 		}
 
-		var issueData struct {
-			Title string `json:"title"`
-			Body  string `json:"body"`
-		}
-
-		if err := json.Unmarshal(output, &issueData); err != nil {
-			fmt.Fprintf(os.Stderr, "Error parsing issue: %v\n", err)
-			os.Exit(1)
-		}
-
-		var currentContent string
-		switch field {
-		case "body":
-			currentContent = issueData.Body
-		case "title":
-			currentContent = issueData.Title
-		}
-
-		newContent, err = internal.OpenEditorWithContent(currentContent, field)
+		currentContent := issueField(issue, field)
+		d := &draft{Kind: draftEdit, Number: issueNum, Field: field, Base: currentContent, UpdatedAt: issue.UpdatedAt}
+		newContent, err = editDraft(d, currentContent)
 		if err != nil {
 			fmt.Fprintf(os.Stderr, "Error opening editor: %v\n", err)
 			os.Exit(1)
 		}
 
-		if strings.TrimSpace(newContent) == strings.TrimSpace(currentContent) {
+		applyFieldEdit(b, issue, field, currentContent, newContent, d, dryRun)
+		return
+	}
+
+	updateField(b, issueNum, field, newContent, nil, dryRun)
+}
+
+// applyFieldEdit saves an edited title or body. base is the text the editor
+// started from; changes made to the issue since are merged.
+func applyFieldEdit(b backend.Backend, issue internal.Issue, field, base, content string, d *draft, dryRun bool) {
+	if strings.TrimSpace(content) == strings.TrimSpace(base) {
+		d.discard()
+		fmt.Println("No changes made")
+		return
+	}
+
+	// Someone may have edited the issue while the editor was open
+	if latest, changed := refetchIfUpdated(b, issue); changed {
+		latestContent := strings.TrimSpace(issueField(latest, field))
+		content = mergeEdit(issue.Number, field, strings.TrimSpace(base), strings.TrimSpace(content), latestContent, d)
+		if content == latestContent {
+			d.discard()
 			fmt.Println("No changes made")
 			return
 		}
 	}
 
-	updateCmd := exec.Command("gh", "issue", "edit", issueNum,
-		"--repo", repo,
-		updateFlag, newContent)
+	updateField(b, issue.Number, field, content, d, dryRun)
+}
+
+// updateField writes the title or body, discarding the draft it came from
+// once saved
+func updateField(b backend.Backend, number int, field, content string, d *draft, dryRun bool) {
+	var update backend.Update
+	switch field {
+	case "body":
+		update.Body = &content
+	case "title":
+		update.Title = &content
+	}
 
-	if err := updateCmd.Run(); err != nil {
+	if err := b.Update(number, update); err != nil {
 		fmt.Fprintf(os.Stderr, "Error updating issue: %v\n", err)
+		d.hint()
+		os.Exit(1)
+	}
+	d.discard()
+	if dryRun {
+		return
+	}
+
+	fmt.Printf("Updated %s for issue #%d\n", field, number)
+}
+
+// editIssueDocument edits an issue as a markdown document: frontmatter with
+// title, priority, labels, assignees and state, followed by the body. Changed
+// fields are applied in one update. The document can also be piped in.
+func editIssueDocument(b backend.Backend, number int, dryRun bool) {
+	issue, err := b.Get(number)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error fetching issue: %v\n", err)
+		os.Exit(1)
+	}
+	current := formatIssueDocument(issue)
+
+	var content string
+	var d *draft
+	stat, _ := os.Stdin.Stat()
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		content, err = readFromStdin()
+	} else {
+		d = &draft{Kind: draftEdit, Number: number, Field: "issue", Base: current, UpdatedAt: issue.UpdatedAt}
+		content, err = editDraft(d, current)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting content: %v\n", err)
+		os.Exit(1)
+	}
+
+	applyDocumentEdit(b, issue, current, content, d, dryRun)
+}
+
+// applyDocumentEdit applies an edited issue document. current is the
+// document the editor started from; changes made to the issue since are
+// merged.
+func applyDocumentEdit(b backend.Backend, issue internal.Issue, current, content string, d *draft, dryRun bool) {
+	number := issue.Number
+
+	// Someone may have edited the issue while the editor was open; their
+	// version becomes the base the changes are computed against
+	latest, changed := refetchIfUpdated(b, issue)
+	if changed {
+		content = mergeEdit(number, "issue", strings.TrimSpace(current), strings.TrimSpace(content),
+			strings.TrimSpace(formatIssueDocument(latest)), d)
+	}
+	issue = latest
+
+	edited, err := parseIssueDocument(content)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if d != nil {
+			d.hint()
+		} else if path, saveErr := saveRejectedEdit(content, "issue"); saveErr == nil {
+			fmt.Fprintf(os.Stderr, "Your edit was saved to %s\n", path)
+		}
 		os.Exit(1)
 	}
 
-	fmt.Printf("Updated %s for issue #%s\n", field, issueNum)
+	update, fields := diffIssueDocument(issue, edited)
+	closing := edited.State != issue.State && edited.State == internal.StateClosed
+	reopening := edited.State != issue.State && edited.State == internal.StateOpen
+	if closing || reopening {
+		fields = append(fields, "state ("+strings.ToLower(edited.State)+")")
+	}
+	if len(fields) == 0 {
+		d.discard()
+		fmt.Println("No changes made")
+		return
+	}
+
+	if len(update.AddLabels) > 0 {
+		ensureLabels(b, update.AddLabels)
+	}
+	if !update.IsEmpty() {
+		if err := b.Update(number, update); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating issue: %v\n", err)
+			d.hint()
+			os.Exit(1)
+		}
+	}
+	if closing {
+		err = b.Close(number)
+	} else if reopening {
+		err = b.Reopen(number)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error changing state of issue: %v\n", err)
+		d.hint()
+		os.Exit(1)
+	}
+	d.discard()
+	if dryRun {
+		return
+	}
+
+	fmt.Printf("Updated #%d: %s\n", number, strings.Join(fields, ", "))
+}
+
+// refetchIfUpdated reads the issue again and reports whether its updatedAt
+// moved since issue was read. On failure issue is returned unchanged.
+func refetchIfUpdated(b backend.Backend, issue internal.Issue) (internal.Issue, bool) {
+	latest, err := b.Get(issue.Number)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: could not check for concurrent changes: %v\n", err)
+		return issue, false
+	}
+	return latest, latest.UpdatedAt != issue.UpdatedAt
+}
+
+// issueField returns the title or body of issue
+func issueField(issue internal.Issue, field string) string {
+	if field == "title" {
+		return issue.Title
+	}
+	return issue.Body
+}
+
+// formatIssueDocument renders the editable fields of issue. Priority labels
+// are shown as the priority field rather than among the labels.
+func formatIssueDocument(issue internal.Issue) string {
+	var labels, assignees []string
+	for _, label := range issue.Labels {
+		if !isPriority(label.Name) {
+			labels = append(labels, label.Name)
+		}
+	}
+	for _, user := range issue.Assignees {
+		assignees = append(assignees, user.Login)
+	}
+
+	return internal.FormatFrontmatter([]internal.FrontmatterField{
+		{Key: "title", Value: issue.Title},
+		{Key: "priority", Value: internal.ExtractPriority(issue)},
+		{Key: "labels", Value: internal.FormatFrontmatterList(labels)},
+		{Key: "assignees", Value: internal.FormatFrontmatterList(assignees)},
+		{Key: "state", Value: strings.ToLower(issue.State)},
+	}, issue.Body)
+}
+
+// parseIssueDocument reads a document written by formatIssueDocument back
+// into an issue
+func parseIssueDocument(content string) (internal.Issue, error) {
+	fields, body := internal.ParseFrontmatter(content)
+	if len(fields) == 0 {
+		return internal.Issue{}, fmt.Errorf("missing frontmatter (the document must start with ---)")
+	}
+
+	issue := internal.Issue{
+		Title: strings.TrimSpace(fields["title"]),
+		Body:  strings.TrimSpace(body),
+	}
+	if issue.Title == "" {
+		return internal.Issue{}, fmt.Errorf("title must not be empty")
+	}
+
+	priority := strings.ToUpper(strings.TrimSpace(fields["priority"]))
+	if !isPriority(priority) {
+		return internal.Issue{}, fmt.Errorf("invalid priority: %q (must be P0-P3)", fields["priority"])
+	}
+	issue.Labels = append(issue.Labels, internal.Label{Name: priority})
+	for _, name := range internal.ParseFrontmatterList(fields["labels"]) {
+		if isPriority(name) {
+			return internal.Issue{}, fmt.Errorf("set the priority with the priority field, not label %s", name)
+		}
+		issue.Labels = append(issue.Labels, internal.Label{Name: name})
+	}
+	for _, login := range internal.ParseFrontmatterList(fields["assignees"]) {
+		issue.Assignees = append(issue.Assignees, internal.User{Login: strings.TrimPrefix(login, "@")})
+	}
+
+	switch strings.ToLower(strings.TrimSpace(fields["state"])) {
+	case "open":
+		issue.State = internal.StateOpen
+	case "closed":
+		issue.State = internal.StateClosed
+	default:
+		return internal.Issue{}, fmt.Errorf("invalid state: %q (must be open or closed)", fields["state"])
+	}
+
+	for key := range fields {
+		switch key {
+		case "title", "priority", "labels", "assignees", "state":
+		default:
+			return internal.Issue{}, fmt.Errorf("unknown field: %s", key)
+		}
+	}
+	return issue, nil
+}
+
+// diffIssueDocument returns the update turning issue into edited (state
+// aside) and the names of the changed fields
+func diffIssueDocument(issue, edited internal.Issue) (backend.Update, []string) {
+	var update backend.Update
+	var changed []string
+
+	if edited.Title != issue.Title {
+		update.Title = &edited.Title
+		changed = append(changed, "title")
+	}
+	if edited.Body != strings.TrimSpace(issue.Body) {
+		update.Body = &edited.Body
+		changed = append(changed, "body")
+	}
+
+	oldPriority := internal.ExtractPriority(issue)
+	newPriority := internal.ExtractPriority(edited)
+	if newPriority != oldPriority {
+		changed = append(changed, fmt.Sprintf("priority (%s → %s)", oldPriority, newPriority))
+	}
+
+	var oldLabels, newLabels []string
+	for _, label := range issue.Labels {
+		oldLabels = append(oldLabels, label.Name)
+	}
+	for _, label := range edited.Labels {
+		newLabels = append(newLabels, label.Name)
+	}
+	update.AddLabels, update.RemoveLabels = diffNames(oldLabels, newLabels)
+	if newPriority == oldPriority {
+		// A missing priority label reads as P2; don't add it just because
+		update.AddLabels = slices.DeleteFunc(update.AddLabels, isPriority)
+	}
+	if labels := formatNameChanges(update.AddLabels, update.RemoveLabels, isPriority); labels != "" {
+		changed = append(changed, "labels ("+labels+")")
+	}
+
+	var oldUsers, newUsers []string
+	for _, user := range issue.Assignees {
+		oldUsers = append(oldUsers, user.Login)
+	}
+	for _, user := range edited.Assignees {
+		newUsers = append(newUsers, user.Login)
+	}
+	update.AddAssignees, update.RemoveAssignees = diffNames(oldUsers, newUsers)
+	if users := formatNameChanges(update.AddAssignees, update.RemoveAssignees, nil); users != "" {
+		changed = append(changed, "assignees ("+users+")")
+	}
+
+	return update, changed
+}
+
+// diffNames returns the names only in after and the names only in before
+func diffNames(before, after []string) ([]string, []string) {
+	var added, removed []string
+	for _, name := range after {
+		if !slices.Contains(before, name) && !slices.Contains(added, name) {
+			added = append(added, name)
+		}
+	}
+	for _, name := range before {
+		if !slices.Contains(after, name) {
+			removed = append(removed, name)
+		}
+	}
+	return added, removed
+}
+
+// formatNameChanges renders "+a -b", leaving out names matching skip
+func formatNameChanges(added, removed []string, skip func(string) bool) string {
+	var parts []string
+	for _, name := range added {
+		if skip == nil || !skip(name) {
+			parts = append(parts, "+"+name)
+		}
+	}
+	for _, name := range removed {
+		if skip == nil || !skip(name) {
+			parts = append(parts, "-"+name)
+		}
+	}
+	return strings.Join(parts, " ")
+}
+
+// saveRejectedEdit keeps a document that could not be applied, so the edit
+// is not lost
+func saveRejectedEdit(content, kind string) (string, error) {
+	file, err := os.CreateTemp("", "ghtask-"+kind+"-*.md")
+	if err != nil {
+		return "", err
+	}
+	defer file.Close()
+	_, err = file.WriteString(content)
+	return file.Name(), err
 }
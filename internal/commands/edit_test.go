@@ -0,0 +1,61 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// offlineBackend cannot read issues back and records the updates it applies
+type offlineBackend struct {
+	backend.Backend
+	updates []backend.Update
+}
+
+func (o *offlineBackend) Get(number int) (internal.Issue, error) {
+	return internal.Issue{}, errors.New("network down")
+}
+
+func (o *offlineBackend) Update(number int, update backend.Update) error {
+	o.updates = append(o.updates, update)
+	return o.Backend.Update(number, update)
+}
+
+func TestResumedDocumentEditWithoutRefetch(t *testing.T) {
+	t.Setenv("GT_DATA_DIR", t.TempDir())
+
+	inner := backend.NewMarkdown(t.TempDir())
+	issue, err := inner.Create("Fix login", "old body", []string{"P1", "bug"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := inner.Update(issue.Number, backend.Update{AddAssignees: []string{"alice"}}); err != nil {
+		t.Fatal(err)
+	}
+	issue, err = inner.Get(issue.Number)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The draft keeps the document the editor started from
+	original := formatIssueDocument(issue)
+	d := &draft{id: 1, Kind: draftEdit, Number: issue.Number, Field: "issue", Base: original, UpdatedAt: issue.UpdatedAt}
+	edited := strings.Replace(original, "old body", "new body", 1)
+
+	base, err := d.baseIssue()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := &offlineBackend{Backend: inner}
+	applyDocumentEdit(b, base, d.Base, edited, d, false)
+
+	body := "new body"
+	if want := []backend.Update{{Body: &body}}; !reflect.DeepEqual(b.updates, want) {
+		t.Errorf("updates = %+v, want only the body", b.updates)
+	}
+}
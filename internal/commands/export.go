@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"golang.org/x/term"
+)
+
+// ExportIssues writes issues to stdout. The default JSON lines format holds
+// one complete issue (labels, assignees, body, comments, timestamps) per line
+// and is what `gt import` reads back. --format taskwarrior|org|todotxt renders
+// the issues for personal task tools instead. Open issues are exported by
+// default; --all includes closed ones.
+func ExportIssues(args []string) {
+	state := backend.ListOpen
+	format := formatJSON
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--all" || args[i] == "-a":
+			state = backend.ListAll
+		case (args[i] == "--format" || args[i] == "-f") && i+1 < len(args):
+			format = args[i+1]
+			i++
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", args[i])
+			fmt.Fprintln(os.Stderr, "Usage: gt export [--all] [--format json|taskwarrior|org|todotxt]")
+			os.Exit(1)
+		}
+	}
+
+	b := OpenBackendOrDie()
+
+	issues, err := b.List(state)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch format {
+	case formatJSON:
+		sort.Slice(issues, func(i, j int) bool {
+			return issues[i].Number < issues[j].Number
+		})
+		exportJSON(b, issues)
+		return
+	case formatTaskwarrior, formatOrg, formatTodoTxt:
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown format: %s (must be json, taskwarrior, org or todotxt)\n", format)
+		os.Exit(1)
+	}
+
+	sortIssues(issues)
+	source := exportSource()
+
+	switch format {
+	case formatTaskwarrior:
+		fmt.Print(exportTaskwarrior(issues, source))
+	case formatOrg:
+		fmt.Print(exportOrg(issues, source))
+	case formatTodoTxt:
+		fmt.Print(exportTodoTxt(issues, source))
+	}
+}
+
+// exportJSON writes each issue in full as a JSON line
+func exportJSON(b backend.Backend, issues []internal.Issue) {
+	encoder := json.NewEncoder(os.Stdout)
+	progress := term.IsTerminal(int(os.Stderr.Fd()))
+	for i, summary := range issues {
+		// Lists omit bodies and comments, so fetch each issue in full
+		issue, err := b.Get(summary.Number)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error fetching issue #%d: %v\n", summary.Number, err)
+			os.Exit(1)
+		}
+
+		if err := encoder.Encode(issue); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing issue #%d: %v\n", issue.Number, err)
+			os.Exit(1)
+		}
+		if progress {
+			fmt.Fprintf(os.Stderr, "\rExported %d/%d", i+1, len(issues))
+		}
+	}
+	if progress {
+		fmt.Fprintln(os.Stderr)
+	}
+}
+
+// exportSource names the task list being exported (owner/repo or the repo directory)
+func exportSource() string {
+	key, err := repoKey()
+	if err != nil {
+		return "tasks"
+	}
+	if filepath.IsAbs(key) {
+		return filepath.Base(key)
+	}
+	return key
+}
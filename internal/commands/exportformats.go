@@ -0,0 +1,188 @@
+package commands
+
+import (
+	"crypto/sha1"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+)
+
+// Export formats for personal task tools (`gt export --format <name>`)
+const (
+	formatJSON        = "json"
+	formatTaskwarrior = "taskwarrior"
+	formatOrg         = "org"
+	formatTodoTxt     = "todotxt"
+)
+
+// Priority letters used by org-mode and todo.txt (both allow A-Z)
+var priorityLetters = map[string]string{
+	"P0": "A",
+	"P1": "B",
+	"P2": "C",
+	"P3": "D",
+}
+
+// Taskwarrior only has three priorities, so critical and important share H
+var taskwarriorLevels = map[string]string{
+	"P0": "H",
+	"P1": "H",
+	"P2": "M",
+	"P3": "L",
+}
+
+// exportTaskwarrior renders one `task import` compatible JSON object per line.
+// UUIDs are derived from the issue reference, so importing an updated export
+// again updates the same tasks instead of duplicating them.
+func exportTaskwarrior(issues []internal.Issue, source string) string {
+	var sb strings.Builder
+	for _, issue := range issues {
+		task := map[string]any{
+			"uuid":        issueUUID(source, issue.Number),
+			"description": fmt.Sprintf("%s (#%d)", issue.Title, issue.Number),
+			"status":      "pending",
+			"priority":    taskwarriorLevels[internal.ExtractPriority(issue)],
+			"entry":       taskwarriorDate(issue.CreatedAt),
+		}
+		if issue.State == internal.StateClosed {
+			task["status"] = "completed"
+			task["end"] = taskwarriorDate(issue.ClosedAt)
+		}
+		if isActive(issue) {
+			task["start"] = taskwarriorDate(issue.UpdatedAt)
+		}
+		if tags := exportTags(issue); len(tags) > 0 {
+			task["tags"] = tags
+		}
+		if issue.URL != "" {
+			task["annotations"] = []map[string]string{{
+				"entry":       taskwarriorDate(issue.CreatedAt),
+				"description": issue.URL,
+			}}
+		}
+
+		line, _ := json.Marshal(task)
+		sb.Write(line)
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// exportOrg renders an org-mode outline; active issues use the STARTED keyword
+func exportOrg(issues []internal.Issue, source string) string {
+	var sb strings.Builder
+	sb.WriteString("#+TITLE: " + source + "\n")
+	sb.WriteString("#+TODO: TODO STARTED | DONE\n")
+	sb.WriteString("#+PRIORITIES: A D C\n\n")
+
+	for _, issue := range issues {
+		keyword := "TODO"
+		if issue.State == internal.StateClosed {
+			keyword = "DONE"
+		} else if isActive(issue) {
+			keyword = "STARTED"
+		}
+
+		heading := fmt.Sprintf("* %s [#%s] %s", keyword, priorityLetters[internal.ExtractPriority(issue)], issue.Title)
+		if tags := exportTags(issue); len(tags) > 0 {
+			heading += " :" + strings.Join(tags, ":") + ":"
+		}
+		sb.WriteString(heading + "\n")
+
+		sb.WriteString("  :PROPERTIES:\n")
+		sb.WriteString(fmt.Sprintf("  :ISSUE:    %d\n", issue.Number))
+		if issue.URL != "" {
+			sb.WriteString("  :URL:      " + issue.URL + "\n")
+		}
+		if created, err := time.Parse(time.RFC3339, issue.CreatedAt); err == nil {
+			sb.WriteString("  :CREATED:  " + created.Local().Format("[2006-01-02 Mon]") + "\n")
+		}
+		sb.WriteString("  :END:\n")
+	}
+	return sb.String()
+}
+
+// exportTodoTxt renders todo.txt lines: priority, creation date, title, labels
+// as +projects, @active for active issues and issue:/url: key-values
+func exportTodoTxt(issues []internal.Issue, source string) string {
+	var sb strings.Builder
+	for _, issue := range issues {
+		var parts []string
+
+		if issue.State == internal.StateClosed {
+			parts = append(parts, "x")
+			if closed := todoTxtDate(issue.ClosedAt); closed != "" {
+				parts = append(parts, closed)
+			}
+		} else {
+			parts = append(parts, "("+priorityLetters[internal.ExtractPriority(issue)]+")")
+		}
+		if created := todoTxtDate(issue.CreatedAt); created != "" {
+			parts = append(parts, created)
+		}
+
+		parts = append(parts, strings.Join(strings.Fields(issue.Title), " "))
+		for _, tag := range exportTags(issue) {
+			parts = append(parts, "+"+tag)
+		}
+		if isActive(issue) && issue.State != internal.StateClosed {
+			parts = append(parts, "@active")
+		}
+		parts = append(parts, fmt.Sprintf("issue:%d", issue.Number))
+		if issue.URL != "" {
+			parts = append(parts, "url:"+issue.URL)
+		}
+
+		sb.WriteString(strings.Join(parts, " ") + "\n")
+	}
+	return sb.String()
+}
+
+// exportTags returns the labels worth carrying over (not inbox, active or the
+// priority), with characters the target formats reject replaced by _
+func exportTags(issue internal.Issue) []string {
+	priority := internal.ExtractPriority(issue)
+
+	var tags []string
+	for _, label := range issue.Labels {
+		if label.Name == priority || strings.EqualFold(label.Name, "inbox") || strings.EqualFold(label.Name, "active") {
+			continue
+		}
+		tags = append(tags, strings.Map(func(r rune) rune {
+			if r == ' ' || r == ':' || r == '+' || r == '@' {
+				return '_'
+			}
+			return r
+		}, label.Name))
+	}
+	return tags
+}
+
+// issueUUID derives a stable UUID (version 5 layout) from the issue reference
+func issueUUID(source string, number int) string {
+	sum := sha1.Sum([]byte(fmt.Sprintf("ghtask:%s#%d", source, number)))
+	sum[6] = (sum[6] & 0x0f) | 0x50
+	sum[8] = (sum[8] & 0x3f) | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", sum[0:4], sum[4:6], sum[6:8], sum[8:10], sum[10:16])
+}
+
+// taskwarriorDate converts an RFC 3339 timestamp to 20060102T150405Z
+func taskwarriorDate(timestamp string) string {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		parsed = time.Now()
+	}
+	return parsed.UTC().Format("20060102T150405Z")
+}
+
+// todoTxtDate converts an RFC 3339 timestamp to 2006-01-02
+func todoTxtDate(timestamp string) string {
+	parsed, err := time.Parse(time.RFC3339, timestamp)
+	if err != nil {
+		return ""
+	}
+	return parsed.Local().Format("2006-01-02")
+}
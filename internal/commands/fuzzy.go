@@ -0,0 +1,173 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// How well a title fragment matches a title; only the best tier found is
+// considered, so a unique exact or substring match wins outright
+const (
+	matchNone = iota
+	matchFuzzy
+	matchWords
+	matchSubstring
+	matchExact
+)
+
+const (
+	// Candidates listed when a title fragment is ambiguous and nobody can pick
+	maxTitleCandidates = 9
+)
+
+// titleMatch is an issue matching a title fragment
+type titleMatch struct {
+	issue internal.Issue
+	score int
+}
+
+// resolveIssueRefOrDie resolves the issue of a single-issue command: a number
+// (12, #12), a title fragment matched against open issues, or without a
+// reference the issue named by the current branch or picked interactively
+func resolveIssueRefOrDie(b backend.Backend, args []string, commandName string) int {
+	if len(args) == 0 {
+		if number := branchIssueOrZero(b, commandName); number > 0 {
+			return number
+		}
+		return pickOpenIssueOrDie(b, commandName)
+	}
+	if len(args) == 1 {
+		if issueNum, err := strconv.Atoi(strings.TrimPrefix(args[0], "#")); err == nil {
+			return issueNum
+		}
+	}
+
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	return resolveTitleOrDie(issues, strings.Join(args, " "))
+}
+
+// resolveTitleOrDie finds the issue whose title matches query. A single exact
+// or substring match is used directly; several matches, or looser ones (words
+// in any order, letters in order), are offered in a ranked picker, and
+// without a terminal gt exits with the candidates rather than guessing.
+func resolveTitleOrDie(issues []internal.Issue, query string) int {
+	matches, tier := matchTitles(issues, query)
+	if len(matches) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no issue matches %q\n", query)
+		os.Exit(1)
+	}
+	if len(matches) == 1 && tier >= matchSubstring {
+		return matches[0].issue.Number
+	}
+
+	if !isInteractive() {
+		if len(matches) == 1 {
+			fmt.Fprintf(os.Stderr, "Error: no issue contains %q; did you mean (use the number):\n", query)
+		} else {
+			fmt.Fprintf(os.Stderr, "Error: %q matches several issues, use the number:\n", query)
+		}
+		for _, match := range matches[:min(len(matches), maxTitleCandidates)] {
+			fmt.Fprintf(os.Stderr, "  #%-5d %s\n", match.issue.Number, match.issue.Title)
+		}
+		os.Exit(1)
+	}
+
+	candidates := make([]internal.Issue, len(matches))
+	for i, match := range matches {
+		candidates[i] = match.issue
+	}
+	return pickIssueOrDie(candidates, fmt.Sprintf("Issues matching %q", query))
+}
+
+// matchTitles returns the issues in the best match tier for query, best
+// first, and that tier
+func matchTitles(issues []internal.Issue, query string) ([]titleMatch, int) {
+	var matches []titleMatch
+	best := matchNone
+
+	for _, issue := range issues {
+		tier, score := matchTitle(query, issue.Title)
+		if tier == matchNone || tier < best {
+			continue
+		}
+		if tier > best {
+			best = tier
+			matches = nil
+		}
+		matches = append(matches, titleMatch{issue: issue, score: score})
+	}
+
+	sort.SliceStable(matches, func(i, j int) bool {
+		return matches[i].score > matches[j].score
+	})
+	return matches, best
+}
+
+// matchTitle rates how well query matches title (case-insensitive): the whole
+// title, a substring, every word in any order, or its letters in order with
+// gaps (fzf-style). The score ranks matches within a tier.
+func matchTitle(query, title string) (int, int) {
+	q := normalizeTitle(query)
+	t := normalizeTitle(title)
+	if q == "" {
+		return matchNone, 0
+	}
+
+	if q == t {
+		return matchExact, 0
+	}
+
+	if index := strings.Index(t, q); index >= 0 {
+		score := -len(t) // prefer shorter titles
+		if index == 0 || t[index-1] == ' ' {
+			score += 100 // prefer matches starting a word
+		}
+		return matchSubstring, score
+	}
+
+	words := strings.Fields(q)
+	matchesAll := len(words) > 1
+	for _, word := range words {
+		if !strings.Contains(t, word) {
+			matchesAll = false
+			break
+		}
+	}
+	if matchesAll {
+		return matchWords, -len(t)
+	}
+
+	// Letters in order: consecutive letters and word starts score higher
+	score, run, next := 0, 0, 0
+	for _, r := range q {
+		if r == ' ' {
+			continue
+		}
+		index := strings.IndexRune(t[next:], r)
+		if index < 0 {
+			return matchNone, 0
+		}
+		position := next + index
+		if index == 0 {
+			run++
+		} else {
+			run = 1
+		}
+		score += run * 2
+		if position == 0 || t[position-1] == ' ' {
+			score += 3
+		}
+		next = position + len(string(r))
+	}
+	return matchFuzzy, score - len(t)/4
+}
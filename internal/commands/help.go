@@ -8,41 +8,90 @@ func ShowHelp() {
 
 USAGE:
   gt [-v]                       List all open issues
-  gt <number>                   View issue details
+  gt <number>                   View issue details, linked commits and PRs
+  gt view <title>               View the issue matching a title fragment
   gt <number> -e <field> [text] Edit issue (field: body or title)
+  gt <number> -e                Edit title, priority, labels, assignees, state
+                                and body as one document in $EDITOR
   gt <title>                    Create P2 (normal) issue (default)
   gt p0/p1/p2/p3 [-v]           Filter by priority
   gt active [-v]                Show only active tasks
-  gt start <number>             Mark issue as active
-  gt pause <number>             Remove active label (alias: stop)
-  gt done <number>              Close issue
-  gt rm <number>                Delete issue (permanent)
+  gt start <issues>             Mark issues as active
+  gt start <issue> --branch     ...and switch to the issue's git branch
+  gt pause <issues>             Remove active label (alias: stop)
+  gt done <issues>              Close issues
+  gt rm <issues> [--force]      Delete issues (asks first; see TRASH)
+  gt prio <p0-p3> <issues>      Change priority
+  gt label <[+]a,-b> <issues>   Add (+) and remove (-) labels
+  gt trash [list]               Show issues removed in trash mode
+  gt trash restore <issues>     Reopen trashed issues
+  gt trash empty [--force]      Delete trashed issues permanently
+  gt plan [--force]             Triage all open issues in $EDITOR (p0-p3,
+                                done, drop, new lines), applied as a batch
+  gt drafts [list]              Show text kept from failed creates and edits
+  gt drafts resume [id]         Edit a draft again and retry saving it
+  gt drafts discard <ids|--all> Delete drafts
+  gt undo [--list]              Revert the last change (--list: show recent)
+  gt hooks install [--require]  Add "Refs #N" to commits (--require: reject
+                                commits without an issue reference)
+  gt hooks uninstall            Remove the hooks, restoring previous ones
+  gt pr [--draft] [--base <b>]  Push the branch and open a PR for its issue
+                                ("Closes #N"), moving the issue to review
   gt setup                      Create required labels in repo
+  gt sync [remote]              Exchange tasks with a git remote (git backend)
+  gt sync-md <file>             Two-way sync open issues with a markdown checklist
+  gt todos                      Create issues from TODO/FIXME/XXX comments
+  gt changelog [v1..v2]         Issues closed between two tags, as markdown
+                                (--group priority|label, --format keepachangelog)
+  gt export [--all]             Dump issues as JSON lines (--all: include closed)
+  gt export --format <fmt>      Render for taskwarrior, org or todotxt
+  gt import <file> [--repo r]   Recreate issues from an export
+  gt import --from <tool> <file> Import from taskwarrior or dstask [--dry-run]
 
   gt0 <title> [--body [text]]   Create P0 (critical) issue
   gt1 <title> [--body [text]]   Create P1 (important) issue
   gt2 <title> [--body [text]]   Create P2 (normal) issue
   gt3 <title> [--body [text]]   Create P3 (low) issue
 
+ISSUES:
+  start, pause, done, rm, prio and label take any mix of numbers (12, #12),
+  lists (12,13), ranges (120-125) and filters selecting open issues
+  (p0-p3, active, label:<name>). Issues are processed concurrently.
+
+  Every command taking an issue also accepts a title fragment, fuzzy
+  matched against open issues: gt done memory leak. Only a single title
+  containing the fragment is used directly; otherwise you pick one (or, in
+  scripts, gt stops and lists them).
+
+  Leave the issue out (gt start, gt done, gt view, ...) to use the issue of
+  the current branch (see BRANCHES), or to choose it from a picker: type to
+  filter, arrows or Ctrl-N/Ctrl-P to move, Enter to select, Esc to cancel.
+
 FLAGS:
   -v, --verbose                 Show priority labels in output
   -b, --body [text]             Add issue body (inline, editor, or piped)
+  -t, --template <name>         Start the body from an issue template
   -e, --edit <field> [text]     Edit issue field (inline, editor, or piped)
+  --dry-run                     Print the changes instead of making them
+                                (create, edit, start, pause, done, rm, prio,
+                                label, plan, pr, setup)
 
 EXAMPLES:
   gt setup                              # Setup labels for this repo
   gt                                    # List all tasks (colors only)
   gt -v                                 # List all tasks (with priority text)
-  gt 123                                # View issue #123 (colored title + body)
+  gt 123                                # View issue #123 (title, body, linked work)
 
   # Creating issues
   gt refactor legacy code               # Create default P2 task (no body)
   gt1 fix authentication bug            # Create P1 task (no body)
   gt1 fix auth bug --body               # Create P1 task, open $EDITOR for body
   gt1 "task title" --body "body text"   # Create P1 task with inline body
+  gt1 crash on save --template bug      # Body from .github/ISSUE_TEMPLATE/bug.md
   cat template.md | gt1 "title" --body  # Create P1 task with piped body
 
   # Editing issues
+  gt 123 -e                             # Edit all of issue #123 in $EDITOR
   gt 123 -e body                        # Edit issue #123 body in $EDITOR
   gt 123 -e title "New title"           # Update title with inline text
   gt 123 -e body "Updated description"  # Update body with inline text
@@ -53,7 +102,35 @@ EXAMPLES:
   gt start 234                          # Mark #234 as active
   gt pause 234                          # Remove active (keep open)
   gt done 567                           # Close #567
-  gt rm 890                             # Delete #890 (permanent)
+  gt rm 890                             # Delete #890 (asks for confirmation)
+
+  # Several issues at once (lists, ranges, filters)
+  gt done 12 15 120-125                 # Close eight issues
+  gt done p3 label:stale                # Close open P3 issues labeled stale
+  gt prio p1 41,42                      # Move #41 and #42 to P1
+  gt label +bug,-inbox 7-9              # Add bug, remove inbox
+  gt done p3 --dry-run                  # Show what would be closed
+  gt start "memory leak"                # Title fragment instead of number
+  gt undo                               # Reopen them again (or restore a
+                                        # deleted issue from the log)
+
+  # Backup and migration
+  gt export --all > tasks.jsonl         # Every issue, comments included
+  gt import tasks.jsonl --repo me/new   # Recreate them in another repo
+  task export > tw.json
+  gt import --from taskwarrior tw.json -n  # Preview a Taskwarrior import
+  dstask show-open | gt import --from dstask -
+  gt export -f taskwarrior | task import  # Shared tasks in Taskwarrior
+  gt export -f org > ~/org/project.org  # ...or org-mode / todo.txt
+
+  # Source comments
+  gt todos                              # // TODO(P1): fix this → P1 issue
+                                        # removed comments → offer to close
+
+  # Markdown checklist
+  gt sync-md TASKS.md                   # Write open issues to TASKS.md
+  gt sync-md TASKS.md                   # ...edit it, then apply: [x] closes,
+                                        # new lines create, moved lines reprioritize
 
 WORKFLOW:
   gt2 <title>   - Creates a P2 issue
@@ -63,6 +140,41 @@ SETUP:
   1. Run: gh auth login
   2. Navigate to a git repo with GitHub remote
   3. Run: gt setup (creates all required labels)
+
+BRANCHES:
+  gt start 123 --branch creates (or checks out) a branch named by
+  ghtask.branch-template, default {number}-{slug} → 123-fix-auth-bug.
+  Placeholders: {number}, {slug}, {priority}. Set ghtask.branch true (or
+  GT_BRANCH=1) to always do it; --no-branch skips it once. On such a branch,
+  gt done, gt view, gt pause, ... without a number act on its issue (the
+  slug must match its title; gt done and gt rm ask first).
+
+  gt hooks install adds "Refs #N" to commit messages: the branch's issue,
+  or your single active issue. gt pr pushes the branch and opens a pull
+  request titled after its issue; the issue moves from active to review.
+
+VIEW:
+  gt <number> renders the body's markdown (headings, lists, task boxes,
+  code, quotes, links) to the terminal width; piped output stays as written.
+  ghtask.markdown false (or GT_MARKDOWN=0) turns rendering off.
+
+EDITOR:
+  ghtask.editor (or GT_EDITOR), then $VISUAL, then $EDITOR; arguments are
+  allowed: "code --wait". ghtask.editor.<kind> overrides it for body, title,
+  issue (gt 123 -e), plan or merge.
+
+TRASH:
+  git config ghtask.trash true  (or GT_TRASH=1) makes gt rm close issues and
+  label them "trashed" instead of deleting them; gt trash restore brings
+  them back, gt trash empty deletes them for good.
+
+BACKENDS:
+  github (default)  Tasks are GitHub Issues of the repo's origin remote
+  git               Tasks live in git refs (refs/ghtask/*), fully offline;
+                    share them with: gt sync [remote]
+  markdown          Tasks are markdown files with YAML frontmatter in .tasks/
+
+  Select with: git config ghtask.backend git  (or GT_BACKEND=git)
 `
 	fmt.Print(help)
 }
@@ -0,0 +1,365 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+	"github.com/DeprecatedLuar/ghtask/internal/github"
+)
+
+const (
+	hookPrepareCommitMsg = "prepare-commit-msg"
+	hookCommitMsg        = "commit-msg"
+
+	// Marker identifying hooks written by gt
+	hookMarker = "# Installed by gt (ghtask)"
+
+	// Suffix of a pre-existing hook set aside by gt hooks install
+	hookBackupSuffix = ".gt-backup"
+
+	hookFilePerms = 0755
+
+	// Line above the diff git adds to the message with commit -v
+	commitScissors = "# ------------------------ >8 ------------------------"
+
+	// How long a commit waits for the backend to name its issue; past it the
+	// message is left alone rather than stalling git offline
+	commitIssueTimeout = 3 * time.Second
+)
+
+var (
+	// commitRefRe matches an issue reference in a commit message (#12, owner/repo#12)
+	commitRefRe = regexp.MustCompile(`(^|[^\w&])#\d+\b`)
+
+	// Messages the validator lets through without a reference
+	exemptCommitRe = regexp.MustCompile(`^(Merge|Revert|fixup!|squash!|amend!) `)
+)
+
+// HooksCommand installs and removes git hooks tying commits to issues:
+//
+//	gt hooks install [--require]   prepare-commit-msg appends "Refs #N";
+//	                               --require adds a commit-msg validator
+//	gt hooks uninstall             remove them, restoring previous hooks
+//	gt hooks [status]              show what is installed
+//
+// The hooks call back into gt (gt hooks run <hook> ...).
+func HooksCommand(args []string) {
+	subcommand := "status"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	if subcommand == "run" {
+		runHook(args)
+		return
+	}
+
+	dir, err := hooksDir()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	switch subcommand {
+	case "install":
+		require := len(args) > 0 && args[0] == "--require"
+		if err := installHook(dir, hookPrepareCommitMsg); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+		if require {
+			err = installHook(dir, hookCommitMsg)
+		} else {
+			err = uninstallHook(dir, hookCommitMsg)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
+
+	case "uninstall":
+		for _, name := range []string{hookPrepareCommitMsg, hookCommitMsg} {
+			if err := uninstallHook(dir, name); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+				os.Exit(1)
+			}
+		}
+
+	case "status":
+		for _, name := range []string{hookPrepareCommitMsg, hookCommitMsg} {
+			path := filepath.Join(dir, name)
+			status := "not installed"
+			if isGtHook(path) {
+				status = "installed"
+				if fileExists(path + hookBackupSuffix) {
+					status += " (runs the previous hook first)"
+				}
+			} else if fileExists(path) {
+				status = "another hook is installed"
+			}
+			fmt.Printf("%-20s %s\n", name, status)
+		}
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown hooks command: %s\n", subcommand)
+		fmt.Fprintln(os.Stderr, "Usage: gt hooks [status | install [--require] | uninstall]")
+		os.Exit(1)
+	}
+}
+
+// installHook writes gt's hook, setting aside a hook that is already there
+func installHook(dir, name string) error {
+	path := filepath.Join(dir, name)
+
+	if fileExists(path) && !isGtHook(path) {
+		if fileExists(path + hookBackupSuffix) {
+			return fmt.Errorf("%s exists and so does its backup %s; remove one first", path, path+hookBackupSuffix)
+		}
+		if err := os.Rename(path, path+hookBackupSuffix); err != nil {
+			return fmt.Errorf("failed to back up %s: %w", path, err)
+		}
+		fmt.Printf("  Moved existing %s to %s (it still runs first)\n", name, name+hookBackupSuffix)
+	}
+
+	executable, err := os.Executable()
+	if err != nil {
+		executable = "gt"
+	}
+
+	// The previous hook runs first; a failing prepare hook never blocks a commit
+	script := fmt.Sprintf(`#!/bin/sh
+%s: gt hooks uninstall restores the previous hook
+if [ -x "$0%s" ]; then
+	"$0%s" "$@" || exit $?
+fi
+gt=%s
+command -v "$gt" >/dev/null 2>&1 || gt=gt
+`, hookMarker, hookBackupSuffix, hookBackupSuffix, shellQuote(executable))
+	if name == hookPrepareCommitMsg {
+		script += fmt.Sprintf("\"$gt\" hooks run %s \"$@\" || true\n", name)
+	} else {
+		script += fmt.Sprintf("exec \"$gt\" hooks run %s \"$@\"\n", name)
+	}
+
+	if err := os.MkdirAll(dir, hookFilePerms); err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, []byte(script), hookFilePerms); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	fmt.Printf("✓ Installed %s hook\n", name)
+	return nil
+}
+
+// uninstallHook removes gt's hook and puts back the hook it replaced
+func uninstallHook(dir, name string) error {
+	path := filepath.Join(dir, name)
+	if !isGtHook(path) {
+		return nil
+	}
+
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to remove %s: %w", path, err)
+	}
+	if fileExists(path + hookBackupSuffix) {
+		if err := os.Rename(path+hookBackupSuffix, path); err != nil {
+			return fmt.Errorf("failed to restore %s: %w", path, err)
+		}
+		fmt.Printf("✓ Removed %s hook (previous hook restored)\n", name)
+		return nil
+	}
+	fmt.Printf("✓ Removed %s hook\n", name)
+	return nil
+}
+
+// runHook is called by the installed hooks with git's arguments
+func runHook(args []string) {
+	if len(args) < 2 {
+		fmt.Fprintln(os.Stderr, "Usage: gt hooks run <hook> <message-file> [args...]")
+		os.Exit(1)
+	}
+	name, messageFile := args[0], args[1]
+
+	content, err := os.ReadFile(messageFile)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "gt: %v\n", err)
+		os.Exit(1)
+	}
+	message := string(content)
+
+	switch name {
+	case hookPrepareCommitMsg:
+		// Leave merges, squashes and amended commits (source "commit") alone
+		if len(args) > 2 && args[2] != "message" && args[2] != "template" {
+			return
+		}
+		if commitRefRe.MatchString(stripCommitComments(message)) {
+			return
+		}
+		number := commitIssue()
+		if number == 0 {
+			return
+		}
+		message = appendCommitTrailer(message, fmt.Sprintf("Refs #%d", number))
+		if err := os.WriteFile(messageFile, []byte(message), 0644); err != nil {
+			fmt.Fprintf(os.Stderr, "gt: %v\n", err)
+		}
+
+	case hookCommitMsg:
+		text := strings.TrimSpace(stripCommitComments(message))
+		if text == "" || exemptCommitRe.MatchString(text) || commitRefRe.MatchString(text) {
+			return
+		}
+		fmt.Fprintln(os.Stderr, "gt: commit message must reference an issue (e.g. Refs #123)")
+		fmt.Fprintln(os.Stderr, "gt: start an issue (gt start <issue>) so it is added for you, or commit with --no-verify")
+		os.Exit(1)
+
+	default:
+		fmt.Fprintf(os.Stderr, "gt: unknown hook: %s\n", name)
+		os.Exit(1)
+	}
+}
+
+// commitIssue returns the issue a commit belongs to: the one named by the
+// current branch, otherwise the single active issue of the current user
+// (or the single unassigned active issue). 0 when there is none or several,
+// or when the backend does not answer within commitIssueTimeout.
+func commitIssue() int {
+	b := OpenBackendOrDie()
+
+	found := make(chan int, 1)
+	go func() {
+		found <- findCommitIssue(b)
+	}()
+	select {
+	case number := <-found:
+		return number
+	case <-time.After(commitIssueTimeout):
+		fmt.Fprintln(os.Stderr, "gt: timed out looking up the issue of this commit; add Refs #N yourself")
+		return 0
+	}
+}
+
+func findCommitIssue(b backend.Backend) int {
+	if issue, _, ok := branchIssue(b); ok {
+		return issue.Number
+	}
+
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		return 0
+	}
+
+	user := currentUser()
+	var mine, unassigned []internal.Issue
+	for _, issue := range issues {
+		if !isActive(issue) {
+			continue
+		}
+		if len(issue.Assignees) == 0 {
+			unassigned = append(unassigned, issue)
+		}
+		for _, assignee := range issue.Assignees {
+			if user != "" && strings.EqualFold(assignee.Login, user) {
+				mine = append(mine, issue)
+			}
+		}
+	}
+
+	if len(mine) == 1 {
+		return mine[0].Number
+	}
+	if len(mine) == 0 && len(unassigned) == 1 {
+		return unassigned[0].Number
+	}
+	return 0
+}
+
+// currentUser returns the login issues are assigned to in this backend: the
+// GitHub login, or the git user name the local backends record
+func currentUser() string {
+	if config.GetOr("backend", backend.KindGitHub) == backend.KindGitHub {
+		if login, err := github.CurrentUser(); err == nil {
+			return login
+		}
+	}
+	output, err := exec.Command("git", "config", "user.name").Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// appendCommitTrailer adds line at the end of the message text, before git's
+// instruction comments (and the diff of commit -v) and separated from the
+// text by a blank line
+func appendCommitTrailer(message, line string) string {
+	diff := ""
+	if index := strings.Index(message, commitScissors); index >= 0 {
+		message, diff = message[:index], message[index:]
+	}
+	lines := strings.Split(message, "\n")
+
+	end := len(lines)
+	for end > 0 && (strings.HasPrefix(lines[end-1], "#") || strings.TrimSpace(lines[end-1]) == "") {
+		end--
+	}
+
+	var result []string
+	result = append(result, lines[:end]...)
+	if end == 0 {
+		result = append(result, "") // keep the first line free for the subject
+	}
+	result = append(result, "", line)
+	if comments := lines[end:]; len(comments) > 0 {
+		result = append(result, comments...)
+	} else {
+		result = append(result, "")
+	}
+	return strings.Join(result, "\n") + diff
+}
+
+func stripCommitComments(message string) string {
+	if index := strings.Index(message, commitScissors); index >= 0 {
+		message = message[:index]
+	}
+
+	var lines []string
+	for _, line := range strings.Split(message, "\n") {
+		if !strings.HasPrefix(line, "#") {
+			lines = append(lines, line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
+
+func hooksDir() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--path-format=absolute", "--git-path", "hooks").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func isGtHook(path string) bool {
+	content, err := os.ReadFile(path)
+	return err == nil && strings.Contains(string(content), hookMarker)
+}
+
+func fileExists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+// shellQuote quotes s for a POSIX shell
+func shellQuote(s string) string {
+	return "'" + strings.ReplaceAll(s, "'", `'\''`) + "'"
+}
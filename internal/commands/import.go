@@ -0,0 +1,271 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const (
+	// Color for labels created on import that gt does not know about
+	defaultLabelColor = "ededed"
+
+	// Largest JSON line accepted when reading an export
+	maxImportLineSize = 16 << 20
+)
+
+// issueRefRe matches #123 references that are not part of a URL fragment,
+// HTML entity or word
+var issueRefRe = regexp.MustCompile(`(^|[^\w/&#])#(\d+)\b`)
+
+// ImportIssues recreates issues from a `gt export` file. Issues are created in
+// their original order; references to other imported issues (#12) in bodies
+// and comments are rewritten to the new numbers, comments are re-added with
+// their original author and date, and closed issues are closed again.
+//
+// With --from taskwarrior|dstask the file is read as another task manager's
+// export instead (see importFrom).
+func ImportIssues(args []string) {
+	repo, args := parseRepoFlag(args)
+	source, dryRun, args := parseImportFlags(args)
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: file required")
+		fmt.Fprintln(os.Stderr, "Usage: gt import <tasks.jsonl|-> [--repo owner/repo] [--dry-run]")
+		fmt.Fprintln(os.Stderr, "       gt import --from taskwarrior|dstask <file|-> [--dry-run]")
+		os.Exit(1)
+	}
+
+	if source != "" {
+		importFrom(source, args[0], openBackendForRepoOrDie(repo), dryRun)
+		return
+	}
+
+	issues, err := readExport(args[0])
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", args[0], err)
+		os.Exit(1)
+	}
+
+	b := openBackendForRepoOrDie(repo)
+	if dryRun {
+		b = backend.NewDryRun(b, os.Stdout)
+	}
+
+	var labels []string
+	for _, issue := range issues {
+		labels = append(labels, labelNames(issue)...)
+	}
+	ensureLabels(b, labels)
+
+	// First pass: create every issue so the number mapping is complete
+	numbers := map[int]int{}
+	for _, issue := range issues {
+		created, err := b.Create(issue.Title, issue.Body, labelNames(issue))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to import #%d %q: %v\n", issue.Number, issue.Title, err)
+			continue
+		}
+		numbers[issue.Number] = created.Number
+		if !dryRun {
+			fmt.Printf("✓ Imported #%d → #%d: %s\n", issue.Number, created.Number, issue.Title)
+		}
+	}
+
+	// Created issues have no number in a dry run, so the second pass is
+	// only described
+	if dryRun {
+		printImportFollowUps(issues)
+		fmt.Printf("\nDry run: %d issues would be imported\n", len(numbers))
+		return
+	}
+
+	// Second pass: cross-references, comments, assignees and state
+	for _, issue := range issues {
+		number, ok := numbers[issue.Number]
+		if !ok {
+			continue
+		}
+
+		if body := rewriteIssueRefs(issue.Body, numbers); body != issue.Body {
+			if err := b.Update(number, backend.Update{Body: &body}); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to rewrite references: %v\n", number, err)
+			}
+		}
+
+		for _, comment := range issue.Comments {
+			body := fmt.Sprintf("_Originally posted by @%s on %s_\n\n%s",
+				comment.Author.Login, comment.CreatedAt, rewriteIssueRefs(comment.Body, numbers))
+			if err := b.AddComment(number, body); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to add comment: %v\n", number, err)
+			}
+		}
+
+		if len(issue.Assignees) > 0 {
+			var logins []string
+			for _, user := range issue.Assignees {
+				logins = append(logins, user.Login)
+			}
+			if err := b.Update(number, backend.Update{AddAssignees: logins}); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to assign %s: %v\n", number, strings.Join(logins, ", "), err)
+			}
+		}
+
+		if issue.State == internal.StateClosed {
+			if err := b.Close(number); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to close: %v\n", number, err)
+			}
+		}
+	}
+
+	fmt.Printf("\nImport complete: %d of %d issues\n", len(numbers), len(issues))
+}
+
+// printImportFollowUps lists what the second pass of an import would do
+func printImportFollowUps(issues []internal.Issue) {
+	for _, issue := range issues {
+		var steps []string
+		if len(issue.Comments) > 0 {
+			steps = append(steps, plural(len(issue.Comments), "comment"))
+		}
+		for _, user := range issue.Assignees {
+			steps = append(steps, "assign "+user.Login)
+		}
+		if issue.State == internal.StateClosed {
+			steps = append(steps, "close")
+		}
+		if len(steps) > 0 {
+			fmt.Printf("then for #%d: %s\n", issue.Number, strings.Join(steps, ", "))
+		}
+	}
+}
+
+// readExport reads a JSON lines export ("-" for stdin), sorted by number
+func readExport(path string) ([]internal.Issue, error) {
+	var reader io.Reader = os.Stdin
+	if path != "-" {
+		file, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		defer file.Close()
+		reader = file
+	}
+
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxImportLineSize)
+
+	var issues []internal.Issue
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+
+		var issue internal.Issue
+		if err := json.Unmarshal([]byte(line), &issue); err != nil {
+			return nil, fmt.Errorf("line %d: %w", lineNum, err)
+		}
+		issues = append(issues, issue)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(issues, func(i, j int) bool {
+		return issues[i].Number < issues[j].Number
+	})
+	return issues, nil
+}
+
+// rewriteIssueRefs replaces #old references with #new for mapped numbers
+func rewriteIssueRefs(text string, numbers map[int]int) string {
+	return issueRefRe.ReplaceAllStringFunc(text, func(match string) string {
+		parts := issueRefRe.FindStringSubmatch(match)
+		old, _ := strconv.Atoi(parts[2])
+		if number, ok := numbers[old]; ok {
+			return parts[1] + "#" + strconv.Itoa(number)
+		}
+		return match
+	})
+}
+
+// ensureLabels creates the labels the backend does not have yet, using gt's
+// colors for the labels it knows
+func ensureLabels(b backend.Backend, labels []string) {
+	existing := getExistingLabels(b)
+
+	for _, name := range labels {
+		if labelExists(existing, name) {
+			continue
+		}
+
+		spec := labelSpec{name: name, color: defaultLabelColor}
+		for _, required := range requiredLabels {
+			if strings.EqualFold(required.name, name) {
+				spec = required
+			}
+		}
+
+		if err := b.CreateLabel(spec.name, spec.color, spec.desc); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to create label %s: %v\n", name, err)
+			continue
+		}
+		existing = append(existing, name)
+	}
+}
+
+// parseRepoFlag extracts --repo owner/repo from args
+func parseRepoFlag(args []string) (string, []string) {
+	repo := ""
+	remaining := []string{}
+
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--repo" || args[i] == "-R") && i+1 < len(args) {
+			repo = args[i+1]
+			i++
+		} else {
+			remaining = append(remaining, args[i])
+		}
+	}
+
+	return repo, remaining
+}
+
+// parseImportFlags extracts --from <source> and --dry-run from args
+func parseImportFlags(args []string) (string, bool, []string) {
+	source := ""
+	dryRun := false
+	remaining := []string{}
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--from" && i+1 < len(args):
+			source = args[i+1]
+			i++
+		case args[i] == "--dry-run" || args[i] == "-n":
+			dryRun = true
+		default:
+			remaining = append(remaining, args[i])
+		}
+	}
+
+	return source, dryRun, remaining
+}
+
+// openBackendForRepoOrDie opens the GitHub backend for repo when given,
+// or the configured backend (recording the import for `gt undo`) otherwise
+func openBackendForRepoOrDie(repo string) backend.Backend {
+	if repo != "" {
+		return backend.NewGitHub(repo)
+	}
+	return openChangeBackendOrDie(false)
+}
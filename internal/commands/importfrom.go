@@ -0,0 +1,282 @@
+package commands
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// Supported external task managers for `gt import --from`
+const (
+	sourceTaskwarrior = "taskwarrior"
+	sourceDstask      = "dstask"
+)
+
+// Taskwarrior priorities mapped to gt priorities (no priority → P2)
+var taskwarriorPriorities = map[string]string{
+	"H": "P1",
+	"M": "P2",
+	"L": "P3",
+}
+
+// taskwarriorTask is a task from `task export`
+type taskwarriorTask struct {
+	Description string   `json:"description"`
+	Status      string   `json:"status"`
+	Priority    string   `json:"priority"`
+	Project     string   `json:"project"`
+	Tags        []string `json:"tags"`
+	Start       string   `json:"start"`
+	Annotations []struct {
+		Entry       string `json:"entry"`
+		Description string `json:"description"`
+	} `json:"annotations"`
+}
+
+// dstaskTask is a task from dstask's JSON output (any dstask command piped)
+type dstaskTask struct {
+	Summary  string   `json:"summary"`
+	Notes    string   `json:"notes"`
+	Status   string   `json:"status"`
+	Priority string   `json:"priority"`
+	Project  string   `json:"project"`
+	Tags     []string `json:"tags"`
+}
+
+// importFrom imports tasks exported by another task manager. Priorities,
+// tags and projects become labels; annotations become comments. Tasks whose
+// title already exists (open or closed) are skipped. With dryRun nothing is
+// created and the planned issues are printed instead.
+func importFrom(source, path string, b backend.Backend, dryRun bool) {
+	content, err := readImportFile(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	var issues []internal.Issue
+	switch source {
+	case sourceTaskwarrior:
+		issues, err = convertTaskwarrior(content)
+	case sourceDstask:
+		issues, err = convertDstask(content)
+	default:
+		err = fmt.Errorf("unknown source: %s (must be '%s' or '%s')", source, sourceTaskwarrior, sourceDstask)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	existing, err := b.List(backend.ListAll)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	titles := map[string]int{}
+	for _, issue := range existing {
+		titles[normalizeTitle(issue.Title)] = issue.Number
+	}
+
+	var toCreate []internal.Issue
+	skipped := 0
+	for _, issue := range issues {
+		if number, exists := titles[normalizeTitle(issue.Title)]; exists {
+			if number > 0 {
+				fmt.Printf("= Skipping duplicate of #%d: %s\n", number, issue.Title)
+			} else {
+				fmt.Printf("= Skipping duplicate in file: %s\n", issue.Title)
+			}
+			skipped++
+			continue
+		}
+		titles[normalizeTitle(issue.Title)] = 0
+		toCreate = append(toCreate, issue)
+	}
+
+	if dryRun {
+		for _, issue := range toCreate {
+			fmt.Printf("+ %s\n", describeImport(issue))
+		}
+		fmt.Printf("\nDry run: %d to create, %d duplicates skipped\n", len(toCreate), skipped)
+		return
+	}
+
+	var labels []string
+	for _, issue := range toCreate {
+		labels = append(labels, labelNames(issue)...)
+	}
+	ensureLabels(b, labels)
+
+	created := 0
+	for _, issue := range toCreate {
+		newIssue, err := b.Create(issue.Title, issue.Body, labelNames(issue))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to create %q: %v\n", issue.Title, err)
+			continue
+		}
+
+		for _, comment := range issue.Comments {
+			if err := b.AddComment(newIssue.Number, comment.Body); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to add comment: %v\n", newIssue.Number, err)
+			}
+		}
+		if issue.State == internal.StateClosed {
+			if err := b.Close(newIssue.Number); err != nil {
+				fmt.Fprintf(os.Stderr, "✗ #%d: failed to close: %v\n", newIssue.Number, err)
+			}
+		}
+
+		fmt.Printf("✓ Created #%d: %s\n", newIssue.Number, issue.Title)
+		created++
+	}
+
+	fmt.Printf("\nImport complete: %d created, %d duplicates skipped\n", created, skipped)
+}
+
+func convertTaskwarrior(content []byte) ([]internal.Issue, error) {
+	var tasks []taskwarriorTask
+	if err := decodeJSONRecords(content, &tasks); err != nil {
+		return nil, fmt.Errorf("invalid taskwarrior export: %w", err)
+	}
+
+	var issues []internal.Issue
+	for _, task := range tasks {
+		if task.Status == "deleted" || task.Status == "recurring" || task.Description == "" {
+			continue // deleted tasks and recurrence templates are not tasks to import
+		}
+
+		priority, ok := taskwarriorPriorities[strings.ToUpper(task.Priority)]
+		if !ok {
+			priority = "P2"
+		}
+
+		issue := newImportedIssue(task.Description, "", priority, task.Project, task.Tags,
+			task.Status == "completed", task.Start != "")
+		for _, annotation := range task.Annotations {
+			issue.Comments = append(issue.Comments, internal.Comment{
+				Body: fmt.Sprintf("_Annotation from %s_\n\n%s", formatTaskwarriorDate(annotation.Entry), annotation.Description),
+			})
+		}
+		issues = append(issues, issue)
+	}
+	return issues, nil
+}
+
+func convertDstask(content []byte) ([]internal.Issue, error) {
+	var tasks []dstaskTask
+	if err := decodeJSONRecords(content, &tasks); err != nil {
+		return nil, fmt.Errorf("invalid dstask export: %w", err)
+	}
+
+	var issues []internal.Issue
+	for _, task := range tasks {
+		if task.Summary == "" {
+			continue
+		}
+
+		priority := strings.ToUpper(task.Priority)
+		if len(priority) != 2 || priority < "P0" || priority > "P3" {
+			priority = "P2"
+		}
+
+		issues = append(issues, newImportedIssue(task.Summary, task.Notes, priority, task.Project, task.Tags,
+			task.Status == "resolved", task.Status == "active"))
+	}
+	return issues, nil
+}
+
+// newImportedIssue builds an issue with gt's labels: inbox, priority,
+// project:<name>, the tags and active for started tasks
+func newImportedIssue(title, body, priority, project string, tags []string, closed, active bool) internal.Issue {
+	issue := internal.Issue{Title: title, Body: body, State: internal.StateOpen}
+	if closed {
+		issue.State = internal.StateClosed
+	}
+
+	names := []string{"inbox", priority}
+	if project != "" {
+		names = append(names, "project:"+project)
+	}
+	names = append(names, tags...)
+	if active && !closed {
+		names = append(names, "active")
+	}
+
+	for _, name := range names {
+		issue.Labels = append(issue.Labels, internal.Label{Name: name})
+	}
+	return issue
+}
+
+// decodeJSONRecords decodes a JSON array or a stream of JSON objects (one per line)
+func decodeJSONRecords[T any](content []byte, records *[]T) error {
+	content = bytes.TrimSpace(content)
+	if bytes.HasPrefix(content, []byte("[")) {
+		return json.Unmarshal(content, records)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(content))
+	for decoder.More() {
+		var record T
+		if err := decoder.Decode(&record); err != nil {
+			return err
+		}
+		*records = append(*records, record)
+	}
+	return nil
+}
+
+// formatTaskwarriorDate turns 20250102T150405Z into 2025-01-02
+func formatTaskwarriorDate(date string) string {
+	if len(date) >= 8 {
+		return date[0:4] + "-" + date[4:6] + "-" + date[6:8]
+	}
+	return date
+}
+
+func readImportFile(path string) ([]byte, error) {
+	if path == "-" {
+		return io.ReadAll(os.Stdin)
+	}
+	return os.ReadFile(path)
+}
+
+func describeImport(issue internal.Issue) string {
+	description := fmt.Sprintf("[%s] %s", internal.ExtractPriority(issue), issue.Title)
+
+	var extra []string
+	for _, name := range labelNames(issue) {
+		if name != "inbox" && name != internal.ExtractPriority(issue) {
+			extra = append(extra, name)
+		}
+	}
+	if len(extra) > 0 {
+		description += "  labels: " + strings.Join(extra, ", ")
+	}
+	if len(issue.Comments) > 0 {
+		description += fmt.Sprintf("  comments: %d", len(issue.Comments))
+	}
+	if issue.State == internal.StateClosed {
+		description += "  (closed)"
+	}
+	return description
+}
+
+func labelNames(issue internal.Issue) []string {
+	names := make([]string, len(issue.Labels))
+	for i, label := range issue.Labels {
+		names[i] = label.Name
+	}
+	return names
+}
+
+func normalizeTitle(title string) string {
+	return strings.ToLower(strings.Join(strings.Fields(title), " "))
+}
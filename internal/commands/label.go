@@ -0,0 +1,47 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// LabelIssues adds and removes labels on issues:
+// gt label <[+]add,-remove,...> <issues...>
+func LabelIssues(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: labels required")
+		fmt.Fprintln(os.Stderr, "Usage: gt label <[+]label,-label,...> <issue-number|range|filter>...")
+		os.Exit(1)
+	}
+
+	var update backend.Update
+	for _, spec := range strings.Split(args[0], ",") {
+		spec = strings.TrimSpace(spec)
+		if name, ok := strings.CutPrefix(spec, "-"); ok && name != "" {
+			update.RemoveLabels = append(update.RemoveLabels, name)
+		} else if name := strings.TrimPrefix(spec, "+"); name != "" {
+			update.AddLabels = append(update.AddLabels, name)
+		}
+	}
+	if len(update.AddLabels) == 0 && len(update.RemoveLabels) == 0 {
+		fmt.Fprintf(os.Stderr, "Error: no labels in %q\n", args[0])
+		os.Exit(1)
+	}
+
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args[1:], "label "+args[0])
+
+	runBulk(numbers, "labeling", func(number int) (string, error) {
+		if err := b.Update(number, update); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("Labeled #%d (%s)", number, args[0]), nil
+	})
+}
@@ -1,14 +1,13 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
-	"os/exec"
 	"sort"
 	"strings"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 	"golang.org/x/term"
 )
 
@@ -20,9 +19,6 @@ const (
 	issueNumWidth        = 5   // Width for issue number formatting (%-5d)
 	issueNumPadding      = 3   // Zero-padding width for verbose mode (03d)
 
-	// List fetching
-	maxIssueLimit = 1000 // Maximum number of issues to fetch from GitHub
-
 	// Color codes
 	colorBlackText = 0   // Black text for active issues
 	colorGrayZeros = 235 // Gray color for leading zeros in verbose mode
@@ -31,26 +27,14 @@ const (
 func ListIssues(args []string) {
 	verbose, filters := ParseVerboseFlag(args)
 
-	repo := internal.GetRepoOrDie()
-
-	cmd := exec.Command("gh", "issue", "list",
-		"--repo", repo,
-		"--state", "open",
-		"--json", "number,title,labels,createdAt",
-		"--limit", fmt.Sprintf("%d", maxIssueLimit))
+	b := OpenBackendOrDie()
 
-	output, err := cmd.Output()
+	issues, err := b.List(backend.ListOpen)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
 		os.Exit(1)
 	}
 
-	var issues []internal.Issue
-	if err := json.Unmarshal(output, &issues); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing issues: %v\n", err)
-		os.Exit(1)
-	}
-
 	filtered := filterIssues(issues, filters)
 	sortIssues(filtered)
 
@@ -91,6 +75,10 @@ func matchesFilters(issue internal.Issue, filters []string) bool {
 			if !hasLabel(issue, priority) {
 				return false
 			}
+		} else if label, ok := strings.CutPrefix(filter, "label:"); ok {
+			if !hasLabel(issue, label) {
+				return false
+			}
 		}
 	}
 	return true
@@ -131,14 +119,17 @@ func getTerminalWidth() int {
 	return width
 }
 
+// truncateTitle shortens title to maxWidth characters, marking the cut with
+// ">". It counts runes, so multibyte characters are never split.
 func truncateTitle(title string, maxWidth int) string {
-	if len(title) <= maxWidth {
+	runes := []rune(title)
+	if len(runes) <= maxWidth {
 		return title
 	}
 	if maxWidth < 1 {
 		return ""
 	}
-	return title[:maxWidth-1] + ">"
+	return string(runes[:maxWidth-1]) + ">"
 }
 
 func printIssue(issue internal.Issue, index int, verbose bool) {
@@ -0,0 +1,23 @@
+package commands
+
+import "testing"
+
+func TestTruncateTitle(t *testing.T) {
+	tests := []struct {
+		title string
+		width int
+		want  string
+	}{
+		{"short", 10, "short"},
+		{"exactly", 7, "exactly"},
+		{"too long title", 8, "too lon>"},
+		{"ünïcödé wörds", 8, "ünïcödé>"},
+		{"日本語のタイトル", 4, "日本語>"},
+		{"anything", 0, ""},
+	}
+	for _, tt := range tests {
+		if got := truncateTitle(tt.title, tt.width); got != tt.want {
+			t.Errorf("truncateTitle(%q, %d) = %q, want %q", tt.title, tt.width, got, tt.want)
+		}
+	}
+}
@@ -4,12 +4,21 @@ import (
 	"bufio"
 	"fmt"
 	"os"
+	"regexp"
 	"strconv"
 	"strings"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
 )
 
+// issueRangeRe matches an issue number or range: 12, #12, 120-125
+var issueRangeRe = regexp.MustCompile(`^#?\d+(-\d+)?$`)
+
+const (
+	// Largest range accepted in an issue reference (120-125)
+	maxIssueRange = 1000
+)
+
 // ParseVerboseFlag extracts verbose flag from args and returns (verbose, remainingArgs)
 func ParseVerboseFlag(args []string) (bool, []string) {
 	verbose := false
@@ -27,22 +36,138 @@ func ParseVerboseFlag(args []string) (bool, []string) {
 }
 
 // ParseIssueNumber extracts and validates issue number from args
-// Returns the issue number string or error if invalid/missing
-func ParseIssueNumber(args []string, commandName string) (string, error) {
+// Returns the issue number or error if invalid/missing
+func ParseIssueNumber(args []string, commandName string) (int, error) {
 	if len(args) == 0 {
-		return "", fmt.Errorf("issue number required\nUsage: gt %s <issue-number>", commandName)
+		return 0, fmt.Errorf("issue number required\nUsage: gt %s <issue-number>", commandName)
 	}
 
-	issueNum := args[0]
-
-	// Validate it's a number
-	if _, err := strconv.Atoi(issueNum); err != nil {
-		return "", fmt.Errorf("invalid issue number: %s", issueNum)
+	issueNum, err := strconv.Atoi(args[0])
+	if err != nil {
+		return 0, fmt.Errorf("invalid issue number: %s", args[0])
 	}
 
 	return issueNum, nil
 }
 
+// IssueRefs are the issue references given to a command
+type IssueRefs struct {
+	Numbers []int    // explicit numbers, lists and ranges
+	Filters []string // filter expressions (p0-p3, active, label:<name>)
+	Titles  []string // title fragments to match against issue titles
+}
+
+// ParseIssueRefs parses the issue references of a bulk command: numbers
+// (123, #123), comma lists (1,2,3), ranges (120-125), filter expressions
+// (p0-p3, active, label:<name>) and title fragments. Consecutive arguments
+// that are not references form one fragment, so quoting is optional.
+// Returns error if invalid/missing
+func ParseIssueRefs(args []string, commandName string) (IssueRefs, error) {
+	var refs IssueRefs
+	if len(args) == 0 {
+		return refs, fmt.Errorf("issue number required\nUsage: gt %s <issue-number|range|filter|title>...", commandName)
+	}
+
+	var words []string
+	flushTitle := func() {
+		if len(words) > 0 {
+			refs.Titles = append(refs.Titles, strings.Join(words, " "))
+			words = nil
+		}
+	}
+
+	for _, arg := range args {
+		if !isIssueRefList(arg) {
+			words = append(words, arg)
+			continue
+		}
+		flushTitle()
+
+		for _, token := range strings.Split(arg, ",") {
+			token = strings.TrimSpace(token)
+			if token == "" {
+				continue
+			}
+
+			if isIssueFilter(token) {
+				refs.Filters = append(refs.Filters, token)
+				continue
+			}
+
+			parsed, err := parseIssueRange(strings.TrimPrefix(token, "#"))
+			if err != nil {
+				return refs, err
+			}
+			refs.Numbers = append(refs.Numbers, parsed...)
+		}
+	}
+	flushTitle()
+
+	return refs, nil
+}
+
+// isIssueRefList reports whether arg is made of numbers, ranges and filters
+// only (anything else is part of a title)
+func isIssueRefList(arg string) bool {
+	for _, token := range strings.Split(arg, ",") {
+		token = strings.TrimSpace(token)
+		if token != "" && !isIssueFilter(token) && !issueRangeRe.MatchString(token) {
+			return false
+		}
+	}
+	return strings.Trim(arg, ", ") != ""
+}
+
+// isIssueFilter reports whether token is a filter expression understood by matchesFilters
+func isIssueFilter(token string) bool {
+	token = strings.ToLower(token)
+	switch token {
+	case "active", "p0", "p1", "p2", "p3":
+		return true
+	}
+	return strings.HasPrefix(token, "label:") && len(token) > len("label:")
+}
+
+// parseIssueRange parses "123" or "120-125" into issue numbers
+func parseIssueRange(token string) ([]int, error) {
+	start, end, isRange := strings.Cut(token, "-")
+	if !isRange {
+		end = start
+	}
+
+	first, err1 := strconv.Atoi(start)
+	last, err2 := strconv.Atoi(end)
+	if err1 != nil || err2 != nil || first <= 0 {
+		return nil, fmt.Errorf("invalid issue number: %s", token)
+	}
+	if last < first || last-first >= maxIssueRange {
+		return nil, fmt.Errorf("invalid issue range: %s (at most %d issues, low to high)", token, maxIssueRange)
+	}
+
+	numbers := make([]int, 0, last-first+1)
+	for number := first; number <= last; number++ {
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+// ParseDryRunFlag extracts --dry-run from args
+// Returns (dryRun, remainingArgs)
+func ParseDryRunFlag(args []string) (bool, []string) {
+	dryRun := false
+	remaining := []string{}
+
+	for _, arg := range args {
+		if arg == "--dry-run" {
+			dryRun = true
+		} else {
+			remaining = append(remaining, arg)
+		}
+	}
+
+	return dryRun, remaining
+}
+
 // ParseBodyFlag extracts --body flag and optional inline value from args
 // Returns (hasBodyFlag, inlineValue, remainingArgs)
 // Example: gt1 "title" --body "text" → returns (true, "text", ["title"])
@@ -70,6 +195,7 @@ func ParseBodyFlag(args []string) (bool, string, []string) {
 // ParseEditFlag extracts -e flag, field (body/title), and optional inline value from args
 // Returns (hasEditFlag, field, inlineValue, remainingArgs, error)
 // Example: gt 123 -e body "new text" → returns (true, "body", "new text", ["123"], nil)
+// A trailing -e has no field (edit the whole issue): gt 123 -e → (true, "", "", ["123"], nil)
 func ParseEditFlag(args []string) (bool, string, string, []string, error) {
 	hasEdit := false
 	field := ""
@@ -91,8 +217,6 @@ func ParseEditFlag(args []string) (bool, string, string, []string, error) {
 					inlineValue = strings.Join(args[i+1:], " ")
 					break // we've consumed the rest
 				}
-			} else {
-				return false, "", "", nil, fmt.Errorf("-e flag requires field argument (body or title)")
 			}
 		} else {
 			remaining = append(remaining, args[i])
@@ -0,0 +1,119 @@
+package commands
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParseIssueRefs(t *testing.T) {
+	tests := []struct {
+		name string
+		args []string
+		want IssueRefs
+		err  string
+	}{
+		{
+			name: "number",
+			args: []string{"12"},
+			want: IssueRefs{Numbers: []int{12}},
+		},
+		{
+			name: "hash number",
+			args: []string{"#12"},
+			want: IssueRefs{Numbers: []int{12}},
+		},
+		{
+			name: "comma list",
+			args: []string{"1,2,#3"},
+			want: IssueRefs{Numbers: []int{1, 2, 3}},
+		},
+		{
+			name: "range",
+			args: []string{"120-123"},
+			want: IssueRefs{Numbers: []int{120, 121, 122, 123}},
+		},
+		{
+			name: "filters",
+			args: []string{"p1", "active,label:bug"},
+			want: IssueRefs{Filters: []string{"p1", "active", "label:bug"}},
+		},
+		{
+			name: "title words form one fragment",
+			args: []string{"fix", "login", "bug"},
+			want: IssueRefs{Titles: []string{"fix login bug"}},
+		},
+		{
+			name: "references split fragments",
+			args: []string{"fix", "login", "4", "memory", "leak", "p0"},
+			want: IssueRefs{Numbers: []int{4}, Filters: []string{"p0"}, Titles: []string{"fix login", "memory leak"}},
+		},
+		{
+			name: "number inside a word is a title",
+			args: []string{"v2"},
+			want: IssueRefs{Titles: []string{"v2"}},
+		},
+		{
+			name: "empty label filter is a title",
+			args: []string{"label:"},
+			want: IssueRefs{Titles: []string{"label:"}},
+		},
+		{
+			name: "missing",
+			args: nil,
+			err:  "issue number required",
+		},
+		{
+			name: "zero",
+			args: []string{"0"},
+			err:  "invalid issue number: 0",
+		},
+		{
+			name: "reversed range",
+			args: []string{"5-3"},
+			err:  "invalid issue range: 5-3",
+		},
+		{
+			name: "range too long",
+			args: []string{"1-1001"},
+			err:  "invalid issue range: 1-1001",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseIssueRefs(tt.args, "done")
+			if tt.err != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.err) {
+					t.Fatalf("ParseIssueRefs(%q) error = %v, want %q", tt.args, err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseIssueRefs(%q) error = %v", tt.args, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseIssueRefs(%q) = %+v, want %+v", tt.args, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseDryRunFlag(t *testing.T) {
+	tests := []struct {
+		args      []string
+		want      bool
+		remaining []string
+	}{
+		{[]string{"12"}, false, []string{"12"}},
+		{[]string{"--dry-run", "12"}, true, []string{"12"}},
+		{[]string{"fix", "bug", "--dry-run"}, true, []string{"fix", "bug"}},
+	}
+
+	for _, tt := range tests {
+		got, remaining := ParseDryRunFlag(tt.args)
+		if got != tt.want || !reflect.DeepEqual(remaining, tt.remaining) {
+			t.Errorf("ParseDryRunFlag(%q) = %v, %q; want %v, %q", tt.args, got, remaining, tt.want, tt.remaining)
+		}
+	}
+}
@@ -2,31 +2,22 @@ package commands
 
 import (
 	"fmt"
-	"os"
-	"os/exec"
 
-	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 )
 
 func PauseIssue(args []string) {
-	issueNum, err := ParseIssueNumber(args, "pause")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
-		os.Exit(1)
-	}
+	dryRun, args := ParseDryRunFlag(args)
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args, "pause")
 
-	repo := internal.GetRepoOrDie()
-
-	cmd := exec.Command("gh", "issue", "edit", issueNum,
-		"--repo", repo,
-		"--remove-label", "active")
-
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error pausing issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "%s\n", output)
-		os.Exit(1)
-	}
-
-	fmt.Printf("✓ Paused #%s\n", issueNum)
+	runBulk(numbers, "pausing", func(number int) (string, error) {
+		if err := b.Update(number, backend.Update{RemoveLabels: []string{"active"}}); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("Paused #%d", number), nil
+	})
 }
@@ -0,0 +1,242 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"golang.org/x/term"
+)
+
+const (
+	// Issues shown at once by the picker
+	pickerHeight = 10
+
+	// Width of the "> #1234 " prefix of picker rows
+	pickerPrefixWidth = 9
+)
+
+// Keys read by the picker in raw mode
+const (
+	keyCtrlC     = 3
+	keyCtrlD     = 4
+	keyCtrlH     = 8
+	keyCtrlJ     = 10
+	keyCtrlK     = 11
+	keyEnter     = 13
+	keyCtrlN     = 14
+	keyCtrlP     = 16
+	keyCtrlU     = 21
+	keyEscape    = 27
+	keyBackspace = 127
+)
+
+// pickOpenIssueOrDie lets the user choose an open issue when a command got no
+// issue reference. Without a terminal it exits with the usual error.
+func pickOpenIssueOrDie(b backend.Backend, commandName string) int {
+	if !isInteractive() {
+		_, err := ParseIssueNumber(nil, commandName)
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	if len(issues) == 0 {
+		fmt.Fprintln(os.Stderr, "No open issues")
+		os.Exit(1)
+	}
+	sortIssues(issues)
+
+	return pickIssueOrDie(issues, fmt.Sprintf("gt %s", commandName))
+}
+
+// pickIssueOrDie runs the picker over issues and exits if it is cancelled
+func pickIssueOrDie(issues []internal.Issue, prompt string) int {
+	issue, ok, err := pickIssue(issues, prompt)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if !ok {
+		fmt.Println("Aborted")
+		os.Exit(1)
+	}
+	return issue.Number
+}
+
+// pickIssue shows an fzf-style picker below the cursor: typing filters the
+// issues (fuzzy, by title or number), arrows or Ctrl-N/Ctrl-P move, Enter
+// selects and Esc or Ctrl-C cancels. Issues keep their order until a query
+// ranks them. Returns false when cancelled.
+func pickIssue(issues []internal.Issue, prompt string) (internal.Issue, bool, error) {
+	fd := int(os.Stdin.Fd())
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return internal.Issue{}, false, fmt.Errorf("failed to read the terminal: %w", err)
+	}
+	defer term.Restore(fd, state)
+
+	query := ""
+	selected, offset := 0, 0
+	matches := issues
+	buf := make([]byte, 16)
+
+	for {
+		if selected >= len(matches) {
+			selected = max(len(matches)-1, 0)
+		}
+		if selected < offset {
+			offset = selected
+		}
+		if selected >= offset+pickerHeight {
+			offset = selected - pickerHeight + 1
+		}
+		drawPicker(prompt, query, matches, len(issues), selected, offset)
+
+		n, err := os.Stdin.Read(buf)
+		if err != nil {
+			clearPicker()
+			return internal.Issue{}, false, err
+		}
+
+		for _, key := range splitKeys(buf[:n]) {
+			switch key {
+			case string(rune(keyCtrlC)), string(rune(keyCtrlD)), string(rune(keyEscape)):
+				clearPicker()
+				return internal.Issue{}, false, nil
+			case string(rune(keyEnter)):
+				clearPicker()
+				if len(matches) == 0 {
+					return internal.Issue{}, false, nil
+				}
+				return matches[selected], true, nil
+			case "\x1b[A", "\x1bOA", string(rune(keyCtrlP)), string(rune(keyCtrlK)):
+				selected = max(selected-1, 0)
+			case "\x1b[B", "\x1bOB", string(rune(keyCtrlN)), string(rune(keyCtrlJ)):
+				selected = min(selected+1, max(len(matches)-1, 0))
+			case string(rune(keyBackspace)), string(rune(keyCtrlH)):
+				if query != "" {
+					_, size := utf8.DecodeLastRuneInString(query)
+					query = query[:len(query)-size]
+					matches, selected, offset = filterPicker(issues, query), 0, 0
+				}
+			case string(rune(keyCtrlU)):
+				query = ""
+				matches, selected, offset = issues, 0, 0
+			default:
+				if r, _ := utf8.DecodeRuneInString(key); unicode.IsPrint(r) {
+					query += key
+					matches, selected, offset = filterPicker(issues, query), 0, 0
+				}
+			}
+		}
+	}
+}
+
+// splitKeys splits terminal input into keys: escape sequences (arrows),
+// single characters and a lone Esc
+func splitKeys(input []byte) []string {
+	var keys []string
+	for len(input) > 0 {
+		if input[0] == keyEscape && len(input) >= 3 && (input[1] == '[' || input[1] == 'O') {
+			// CSI sequences end with a letter or ~ (e.g. \x1b[A, \x1b[3~)
+			end := 2
+			for end < len(input) && !(input[end] >= '@' && input[end] <= '~') {
+				end++
+			}
+			end = min(end+1, len(input))
+			keys = append(keys, string(input[:end]))
+			input = input[end:]
+			continue
+		}
+
+		_, size := utf8.DecodeRune(input)
+		keys = append(keys, string(input[:size]))
+		input = input[size:]
+	}
+	return keys
+}
+
+// filterPicker returns the issues matching query, best first. Numbers match
+// by prefix (12 finds #12 and #120).
+func filterPicker(issues []internal.Issue, query string) []internal.Issue {
+	if strings.TrimSpace(query) == "" {
+		return issues
+	}
+
+	type ranked struct {
+		issue internal.Issue
+		tier  int
+		score int
+	}
+	var results []ranked
+	for _, issue := range issues {
+		tier, score := matchTitle(query, issue.Title)
+		if strings.HasPrefix(strconv.Itoa(issue.Number), strings.TrimPrefix(strings.TrimSpace(query), "#")) {
+			tier, score = matchExact, -issue.Number
+		}
+		if tier != matchNone {
+			results = append(results, ranked{issue, tier, score})
+		}
+	}
+
+	sort.SliceStable(results, func(i, j int) bool {
+		if results[i].tier != results[j].tier {
+			return results[i].tier > results[j].tier
+		}
+		return results[i].score > results[j].score
+	})
+
+	filtered := make([]internal.Issue, len(results))
+	for i, result := range results {
+		filtered[i] = result.issue
+	}
+	return filtered
+}
+
+// drawPicker redraws the prompt line, the visible rows and a match counter,
+// leaving the cursor after the query. The cursor is on the prompt line before
+// each redraw.
+func drawPicker(prompt, query string, matches []internal.Issue, total, selected, offset int) {
+	width := getTerminalWidth()
+	if width <= pickerPrefixWidth {
+		width = defaultTerminalWidth // some terminals report no size
+	}
+	reset := "\033[0m"
+
+	var sb strings.Builder
+	sb.WriteString("\r\033[J")
+
+	end := min(offset+pickerHeight, len(matches))
+	for i := offset; i < end; i++ {
+		issue := matches[i]
+		color := internal.GetPriorityColor(internal.ExtractPriority(issue))
+		title := truncateTitle(issue.Title, width-pickerPrefixWidth)
+
+		marker := "  "
+		if i == selected {
+			marker = "\033[1m> "
+		}
+		fmt.Fprintf(&sb, "\r\n%s%s#%-5d %s%s", marker, color, issue.Number, title, reset)
+	}
+	fmt.Fprintf(&sb, "\r\n\033[2m  %d/%d%s", len(matches), total, reset)
+
+	// Back up to the prompt line and write the query there
+	fmt.Fprintf(&sb, "\033[%dA\r%s> %s", end-offset+1, prompt, query)
+	fmt.Print(sb.String())
+}
+
+// clearPicker erases the picker, leaving the cursor where it started
+func clearPicker() {
+	fmt.Print("\r\033[J")
+}
@@ -0,0 +1,343 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+// Actions of gt plan lines besides the priorities
+const (
+	planDone = "done"
+	planDrop = "drop"
+)
+
+// A plan line: "<action> [#N] title"
+var planLineRe = regexp.MustCompile(`^(\S+)\s+(?:#(\d+)\s*)?(.*)$`)
+
+const planHeader = `# gt plan: change the first word of a line, then save and quit.
+#   p0-p3  set the priority
+#   done   close the issue
+#   drop   delete the issue (trash it in trash mode)
+# Add "p2 Title" lines to create issues and edit titles to rename. Lines can
+# be moved around; removing a line leaves its issue alone. Lines starting with
+# # are ignored, and an unchanged plan does nothing.
+`
+
+// planChange is what gt plan does to one issue; number 0 creates an issue
+type planChange struct {
+	line     int // index of the plan line
+	number   int
+	title    string // new title, or the issue's
+	oldTitle string // set when renaming
+	priority string // new priority, or ""
+	action   string // planDone, planDrop or ""
+}
+
+// PlanIssues opens every open issue in the editor as a "p1 #123 title" line
+// and applies the edited plan in one batch (rebase-style triage). The plan is
+// kept as a draft until it is applied, and can be piped in instead; without a
+// terminal --force skips the confirmation.
+func PlanIssues(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
+	force, args := parseForceFlag(args)
+	if len(args) > 0 {
+		fmt.Fprintf(os.Stderr, "Error: unknown argument: %s\n", args[0])
+		fmt.Fprintln(os.Stderr, "Usage: gt plan [--force] [--dry-run]")
+		os.Exit(1)
+	}
+
+	b := openChangeBackendOrDie(dryRun)
+	issues := listPlanIssuesOrDie(b)
+
+	var content string
+	var d *draft
+	var err error
+	stat, _ := os.Stdin.Stat()
+	if (stat.Mode() & os.ModeCharDevice) == 0 {
+		content, err = readFromStdin()
+	} else {
+		d = &draft{Kind: draftPlan}
+		content, err = editDraft(d, formatPlan(issues))
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error getting plan: %v\n", err)
+		os.Exit(1)
+	}
+
+	applyPlanText(b, issues, content, d, force, dryRun)
+}
+
+// listPlanIssuesOrDie returns the open issues a plan is made of, in list order
+func listPlanIssuesOrDie(b backend.Backend) []internal.Issue {
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	sortIssues(issues)
+	return issues
+}
+
+// applyPlanText confirms and applies an edited plan. The draft it came from
+// is kept until every change is applied.
+func applyPlanText(b backend.Backend, issues []internal.Issue, content string, d *draft, force, dryRun bool) {
+	changes, err := parsePlan(content, issues)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		if d != nil {
+			d.hint()
+		} else if path, saveErr := saveRejectedEdit(content, "plan"); saveErr == nil {
+			fmt.Fprintf(os.Stderr, "Your plan was saved to %s\n", path)
+		}
+		os.Exit(1)
+	}
+	if len(changes) == 0 {
+		d.discard()
+		fmt.Println("No changes made")
+		return
+	}
+
+	trash := config.GetBool("trash")
+	if !dryRun && !force {
+		printPlan(changes, trash)
+		if !isInteractive() {
+			fmt.Fprintln(os.Stderr, "Error: refusing to apply the plan without confirmation (not a terminal); use --force")
+			d.hint()
+			os.Exit(1)
+		}
+		if !confirm(fmt.Sprintf("Apply %s?", plural(len(changes), "change"))) {
+			d.discard()
+			fmt.Println("Aborted")
+			os.Exit(1)
+		}
+	}
+
+	if !applyPlan(b, changes, content, d, trash, dryRun) {
+		d.hint()
+		os.Exit(1)
+	}
+	d.discard()
+}
+
+// formatPlan renders one line per issue, in list order
+func formatPlan(issues []internal.Issue) string {
+	var sb strings.Builder
+	sb.WriteString(planHeader + "\n")
+	for _, issue := range issues {
+		fmt.Fprintf(&sb, "%s #%d %s\n", strings.ToLower(internal.ExtractPriority(issue)), issue.Number, issue.Title)
+	}
+	return sb.String()
+}
+
+// parsePlan compares an edited plan with the open issues and returns the
+// changes it asks for, in plan order
+func parsePlan(content string, issues []internal.Issue) ([]planChange, error) {
+	open := map[int]internal.Issue{}
+	for _, issue := range issues {
+		open[issue.Number] = issue
+	}
+
+	var changes []planChange
+	seen := map[int]bool{}
+	for i, line := range strings.Split(content, "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+
+		groups := planLineRe.FindStringSubmatch(line)
+		if groups == nil {
+			return nil, fmt.Errorf("line %d: expected \"<p0-p3|done|drop> [#N] title\": %s", i+1, line)
+		}
+		action := strings.ToLower(groups[1])
+		title := strings.TrimSpace(groups[3])
+		if !isPriority(action) && action != planDone && action != planDrop {
+			return nil, fmt.Errorf("line %d: unknown action %q (must be p0-p3, done or drop)", i+1, groups[1])
+		}
+
+		if groups[2] == "" {
+			if !isPriority(action) {
+				return nil, fmt.Errorf("line %d: new issues need a priority, not %s", i+1, action)
+			}
+			if title == "" {
+				return nil, fmt.Errorf("line %d: new issue without a title", i+1)
+			}
+			changes = append(changes, planChange{line: i, title: title, priority: strings.ToUpper(action)})
+			continue
+		}
+
+		number, _ := strconv.Atoi(groups[2])
+		issue, ok := open[number]
+		if !ok {
+			return nil, fmt.Errorf("line %d: #%d is not an open issue", i+1, number)
+		}
+		if seen[number] {
+			return nil, fmt.Errorf("line %d: #%d is listed twice", i+1, number)
+		}
+		seen[number] = true
+
+		change := planChange{line: i, number: number, title: issue.Title}
+		if title != "" && title != issue.Title {
+			change.title, change.oldTitle = title, issue.Title
+		}
+		if isPriority(action) {
+			if priority := strings.ToUpper(action); priority != internal.ExtractPriority(issue) {
+				change.priority = priority
+			}
+		} else {
+			change.action = action
+		}
+
+		if change.oldTitle != "" || change.priority != "" || change.action != "" {
+			changes = append(changes, change)
+		}
+	}
+	return changes, nil
+}
+
+// printPlan lists the changes before asking to apply them
+func printPlan(changes []planChange, trash bool) {
+	dropped := "delete permanently"
+	if trash {
+		dropped = "move to trash"
+	}
+
+	fmt.Println("Plan:")
+	for _, change := range changes {
+		ref := fmt.Sprintf("#%d", change.number)
+		if change.number == 0 {
+			ref = "new"
+		}
+
+		var what []string
+		if change.priority != "" {
+			what = append(what, change.priority)
+		}
+		if change.oldTitle != "" {
+			what = append(what, fmt.Sprintf("rename from %q", change.oldTitle))
+		}
+		switch change.action {
+		case planDone:
+			what = append(what, "close")
+		case planDrop:
+			what = append(what, dropped)
+		}
+		fmt.Printf("  %-6s %s  (%s)\n", ref, change.title, strings.Join(what, ", "))
+	}
+}
+
+// applyPlan creates the new issues, then changes the others concurrently,
+// and reports whether everything was applied. Applied lines are commented
+// out in the plan text of the draft, so retrying it only repeats what failed.
+func applyPlan(b backend.Backend, changes []planChange, content string, d *draft, trash, dryRun bool) bool {
+	byNumber := map[int]planChange{}
+	var numbers []int
+	lines := strings.Split(content, "\n")
+	var mu sync.Mutex
+	failed := false
+
+	for _, change := range changes {
+		if change.number != 0 {
+			byNumber[change.number] = change
+			numbers = append(numbers, change.number)
+			continue
+		}
+
+		issue, err := b.Create(change.title, "", []string{"inbox", change.priority})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Error creating %q: %v\n", change.title, err)
+			failed = true
+			continue
+		}
+		lines[change.line] = fmt.Sprintf("# %s (created #%d)", strings.TrimSpace(lines[change.line]), issue.Number)
+		if !dryRun {
+			fmt.Printf("✓ Created: %s\n", issueRef(issue))
+		}
+	}
+
+	if trash {
+		ensureLabels(b, []string{trashLabel})
+	}
+
+	if len(numbers) > 0 && runBulkFailed(numbers, "updating", func(number int) (string, error) {
+		change := byNumber[number]
+		message, err := applyPlanChange(b, change, trash, dryRun)
+		if err == nil {
+			mu.Lock()
+			lines[change.line] = "# " + strings.TrimSpace(lines[change.line])
+			mu.Unlock()
+		}
+		return message, err
+	}) > 0 {
+		failed = true
+	}
+
+	if failed && !dryRun {
+		if err := d.save(strings.Join(lines, "\n")); err != nil {
+			fmt.Fprintf(os.Stderr, "Error updating draft: %v\n", err)
+		}
+	}
+	return !failed
+}
+
+func applyPlanChange(b backend.Backend, change planChange, trash, dryRun bool) (string, error) {
+	issue, err := b.Get(change.number)
+	if err != nil {
+		return "", err
+	}
+
+	var update backend.Update
+	var done []string
+	if change.oldTitle != "" {
+		update.Title = &change.title
+		done = append(done, "renamed")
+	}
+	if change.priority != "" {
+		update.AddLabels = []string{change.priority}
+		for _, label := range issue.Labels {
+			if isPriority(label.Name) && !strings.EqualFold(label.Name, change.priority) {
+				update.RemoveLabels = append(update.RemoveLabels, label.Name)
+			}
+		}
+		done = append(done, "→ "+change.priority)
+	}
+	if change.action == planDrop && trash {
+		update.AddLabels = append(update.AddLabels, trashLabel)
+	}
+
+	if change.action == planDrop && !trash {
+		if err := b.Delete(change.number); err != nil {
+			return "", err
+		}
+		done = []string{"deleted"}
+	} else {
+		if !update.IsEmpty() {
+			if err := b.Update(change.number, update); err != nil {
+				return "", err
+			}
+		}
+		switch change.action {
+		case planDone:
+			err = b.Close(change.number)
+			done = append(done, "closed")
+		case planDrop:
+			err = b.Close(change.number)
+			done = append(done, "trashed")
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if dryRun {
+		return "", nil
+	}
+	return fmt.Sprintf("#%d %s: %s", change.number, strings.Join(done, ", "), change.title), nil
+}
@@ -0,0 +1,107 @@
+package commands
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+// flakyBackend fails closing one issue until it is told to recover
+type flakyBackend struct {
+	backend.Backend
+	failClose int
+}
+
+func (f *flakyBackend) Close(number int) error {
+	if number == f.failClose {
+		return errors.New("network down")
+	}
+	return f.Backend.Close(number)
+}
+
+func TestPlanResumesAfterPartialFailure(t *testing.T) {
+	t.Setenv("GT_DATA_DIR", t.TempDir())
+
+	inner := backend.NewMarkdown(t.TempDir())
+	for _, title := range []string{"close me", "fails to close", "drop me", "raise me"} {
+		if _, err := inner.Create(title, "", []string{"inbox", "P2"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	b := &flakyBackend{Backend: inner, failClose: 2}
+
+	content := strings.Join([]string{
+		"done #1 close me",
+		"done #2 fails to close",
+		"drop #3 drop me",
+		"p0 #4 raise me",
+		"p1 new task",
+	}, "\n")
+	d := &draft{id: 1, Kind: draftPlan}
+	if err := d.save(content); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := parsePlan(content, openIssues(t, b))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if applyPlan(b, changes, content, d, false, false) {
+		t.Fatal("applyPlan reported success with a failing change")
+	}
+
+	// Resuming the draft must only retry the change that failed
+	saved := readDraftText(t, d)
+	changes, err = parsePlan(saved, openIssues(t, b))
+	if err != nil {
+		t.Fatalf("resumed plan does not parse: %v\n%s", err, saved)
+	}
+	if len(changes) != 1 || changes[0].number != 2 || changes[0].action != planDone {
+		t.Fatalf("resumed plan changes = %+v, want only done #2\n%s", changes, saved)
+	}
+
+	b.failClose = 0
+	if !applyPlan(b, changes, saved, d, false, false) {
+		t.Fatal("retrying the plan failed")
+	}
+
+	issues, err := b.List(backend.ListAll)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, issue := range issues {
+		got = append(got, issue.Title+" "+issue.State+" "+internal.ExtractPriority(issue))
+	}
+	want := "close me CLOSED P2|fails to close CLOSED P2|raise me OPEN P0|new task OPEN P1"
+	if strings.Join(got, "|") != want {
+		t.Errorf("issues = %q, want %q", strings.Join(got, "|"), want)
+	}
+}
+
+func openIssues(t *testing.T, b backend.Backend) []internal.Issue {
+	t.Helper()
+	issues, err := b.List(backend.ListOpen)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issues
+}
+
+func readDraftText(t *testing.T, d *draft) string {
+	t.Helper()
+	dir, err := config.DataDir("drafts")
+	if err != nil {
+		t.Fatal(err)
+	}
+	text, err := os.ReadFile(d.textPath(dir))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(text)
+}
@@ -0,0 +1,154 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const (
+	// Label of issues whose pull request is waiting for review
+	reviewLabel = "review"
+)
+
+// CreatePullRequest opens a pull request for the current branch's issue:
+// the branch is pushed if needed, the PR takes the issue title and body plus
+// "Closes #N", and the issue moves from active to review.
+func CreatePullRequest(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
+	draft, base, args := parsePullRequestFlags(args)
+
+	b := OpenBackendOrDie()
+	requester, ok := b.(backend.PullRequester)
+	if !ok && !dryRun {
+		fmt.Fprintln(os.Stderr, "Error: pull requests need the github backend")
+		os.Exit(1)
+	}
+
+	if dryRun {
+		planner := backend.NewDryRun(b, os.Stdout)
+		b, requester = planner, planner
+	} else {
+		b = withJournal(b)
+	}
+
+	branch, err := currentBranch()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	if branch == base || branch == "main" || branch == "master" {
+		fmt.Fprintf(os.Stderr, "Error: on %s; create a branch for the issue first (gt start <issue> --branch)\n", branch)
+		os.Exit(1)
+	}
+
+	number := resolveIssueRefOrDie(b, args, "pr")
+	issue, err := b.Get(number)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
+		os.Exit(1)
+	}
+
+	if err := pushBranch(branch, dryRun); err != nil {
+		fmt.Fprintf(os.Stderr, "Error pushing %s: %v\n", branch, err)
+		os.Exit(1)
+	}
+
+	url, err := openPullRequest(b, requester, issue, backend.PullRequest{Head: branch, Base: base, Draft: draft})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error %v\n", err)
+		os.Exit(1)
+	}
+	if dryRun {
+		return
+	}
+
+	fmt.Printf("✓ Opened pull request: %s\n", url)
+	fmt.Printf("✓ #%d moved to review\n", number)
+}
+
+// openPullRequest opens pr (head, base and draft set) for issue, with the
+// issue title and body plus "Closes #N", and moves the issue to review.
+// It returns the pull request URL.
+func openPullRequest(b backend.Backend, requester backend.PullRequester, issue internal.Issue, pr backend.PullRequest) (string, error) {
+	pr.Title = issue.Title
+	pr.Body = strings.TrimSpace(issue.Body)
+	if pr.Body != "" {
+		pr.Body += "\n\n"
+	}
+	pr.Body += fmt.Sprintf("Closes #%d", issue.Number)
+
+	url, err := requester.CreatePullRequest(pr)
+	if err != nil {
+		return "", fmt.Errorf("creating pull request: %w", err)
+	}
+
+	ensureLabels(b, []string{reviewLabel})
+	update := backend.Update{AddLabels: []string{reviewLabel}, RemoveLabels: []string{"active"}}
+	if err := b.Update(issue.Number, update); err != nil {
+		return url, fmt.Errorf("moving #%d to review: %w", issue.Number, err)
+	}
+	return url, nil
+}
+
+// pushBranch pushes branch when it has no upstream yet or has unpushed commits
+func pushBranch(branch string, dryRun bool) error {
+	args := []string{"push"}
+	upstream, err := exec.Command("git", "rev-parse", "--abbrev-ref", branch+"@{upstream}").Output()
+	if err != nil {
+		args = []string{"push", "--set-upstream", defaultSyncRemote, branch}
+	} else {
+		ahead, err := exec.Command("git", "rev-list", "--count", strings.TrimSpace(string(upstream))+".."+branch).Output()
+		if err != nil {
+			return err
+		}
+		if count, _ := strconv.Atoi(strings.TrimSpace(string(ahead))); count == 0 {
+			return nil // already pushed
+		}
+	}
+
+	if dryRun {
+		fmt.Printf("would run: git %s\n", strings.Join(args, " "))
+		return nil
+	}
+
+	if output, err := exec.Command("git", args...).CombinedOutput(); err != nil {
+		return fmt.Errorf("%s", strings.TrimSpace(string(output)))
+	}
+	fmt.Printf("✓ Pushed %s\n", branch)
+	return nil
+}
+
+func currentBranch() (string, error) {
+	output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output()
+	if err != nil {
+		return "", fmt.Errorf("not on a branch")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// parsePullRequestFlags extracts --draft and --base <branch> from args
+func parsePullRequestFlags(args []string) (bool, string, []string) {
+	draft := false
+	base := ""
+	remaining := []string{}
+
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--draft" || args[i] == "-d":
+			draft = true
+		case args[i] == "--base" && i+1 < len(args):
+			base = args[i+1]
+			i++
+		default:
+			remaining = append(remaining, args[i])
+		}
+	}
+
+	return draft, base, remaining
+}
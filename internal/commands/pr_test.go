@@ -0,0 +1,102 @@
+package commands
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// fakePullRequester records the pull requests it is asked to open
+type fakePullRequester struct {
+	opened []backend.PullRequest
+	err    error
+}
+
+func (f *fakePullRequester) CreatePullRequest(pr backend.PullRequest) (string, error) {
+	if f.err != nil {
+		return "", f.err
+	}
+	f.opened = append(f.opened, pr)
+	return "https://github.com/owner/repo/pull/1", nil
+}
+
+func TestOpenPullRequest(t *testing.T) {
+	tests := []struct {
+		name   string
+		body   string
+		labels []string
+		pr     backend.PullRequest
+		err    error
+
+		want       backend.PullRequest
+		wantLabels []string
+		wantErr    string
+	}{
+		{
+			name:       "issue body and closing reference",
+			body:       "Steps to reproduce\n",
+			labels:     []string{"P1", "active"},
+			pr:         backend.PullRequest{Head: "1-fix-login"},
+			want:       backend.PullRequest{Title: "Fix login", Body: "Steps to reproduce\n\nCloses #1", Head: "1-fix-login"},
+			wantLabels: []string{"P1", "review"},
+		},
+		{
+			name:       "empty body, draft against a base",
+			labels:     []string{"P2", "inbox"},
+			pr:         backend.PullRequest{Head: "fix-login", Base: "develop", Draft: true},
+			want:       backend.PullRequest{Title: "Fix login", Body: "Closes #1", Head: "fix-login", Base: "develop", Draft: true},
+			wantLabels: []string{"P2", "inbox", "review"},
+		},
+		{
+			name:       "forge error leaves the issue alone",
+			labels:     []string{"P1", "active"},
+			pr:         backend.PullRequest{Head: "fix-login"},
+			err:        errors.New("no commits between main and fix-login"),
+			wantLabels: []string{"P1", "active"},
+			wantErr:    "creating pull request: no commits between main and fix-login",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b := backend.NewMarkdown(t.TempDir())
+			issue, err := b.Create("Fix login", tt.body, tt.labels)
+			if err != nil {
+				t.Fatal(err)
+			}
+			requester := &fakePullRequester{err: tt.err}
+
+			url, err := openPullRequest(b, requester, issue, tt.pr)
+			if tt.wantErr != "" {
+				if err == nil || err.Error() != tt.wantErr {
+					t.Fatalf("error = %v, want %q", err, tt.wantErr)
+				}
+			} else {
+				if err != nil {
+					t.Fatal(err)
+				}
+				if !strings.HasSuffix(url, "/pull/1") {
+					t.Errorf("url = %q", url)
+				}
+				if len(requester.opened) != 1 || !reflect.DeepEqual(requester.opened[0], tt.want) {
+					t.Errorf("opened %+v, want %+v", requester.opened, tt.want)
+				}
+			}
+
+			updated, err := b.Get(issue.Number)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var labels []string
+			for _, label := range updated.Labels {
+				labels = append(labels, label.Name)
+			}
+			if !reflect.DeepEqual(labels, tt.wantLabels) {
+				t.Errorf("labels = %v, want %v", labels, tt.wantLabels)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+// SetPriority moves issues to another priority: gt prio <p0-p3> <issues...>
+func SetPriority(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
+	if len(args) == 0 || !isPriority(args[0]) {
+		fmt.Fprintln(os.Stderr, "Error: priority required")
+		fmt.Fprintln(os.Stderr, "Usage: gt prio <p0|p1|p2|p3> <issue-number|range|filter>...")
+		os.Exit(1)
+	}
+	priority := strings.ToUpper(args[0])
+
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args[1:], "prio "+args[0])
+
+	runBulk(numbers, "reprioritizing", func(number int) (string, error) {
+		issue, err := b.Get(number)
+		if err != nil {
+			return "", err
+		}
+
+		// Only remove priorities the issue has; GitHub rejects unknown removals
+		var remove []string
+		for _, label := range issue.Labels {
+			if isPriority(label.Name) && !strings.EqualFold(label.Name, priority) {
+				remove = append(remove, label.Name)
+			}
+		}
+
+		update := backend.Update{AddLabels: []string{priority}, RemoveLabels: remove}
+		if err := b.Update(number, update); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("#%d → %s: %s", number, priority, issue.Title), nil
+	})
+}
+
+// isPriority reports whether name is one of the P0-P3 priority labels
+func isPriority(name string) bool {
+	name = strings.ToUpper(name)
+	return len(name) == 2 && name >= "P0" && name <= "P3"
+}
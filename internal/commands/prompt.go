@@ -0,0 +1,29 @@
+package commands
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// isInteractive reports whether a user can answer prompts (stdin and stdout are terminals)
+func isInteractive() bool {
+	return term.IsTerminal(int(os.Stdin.Fd())) && term.IsTerminal(int(os.Stdout.Fd()))
+}
+
+// confirm asks a yes/no question on the terminal; anything but y/yes is no
+func confirm(question string) bool {
+	answer := ask(question + " [y/N] ")
+	answer = strings.ToLower(answer)
+	return answer == "y" || answer == "yes"
+}
+
+// ask prints a prompt and returns the trimmed line typed by the user
+func ask(prompt string) string {
+	fmt.Print(prompt)
+	line, _ := bufio.NewReader(os.Stdin).ReadString('\n')
+	return strings.TrimSpace(line)
+}
@@ -1,33 +1,37 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
-	"os/exec"
 	"strings"
 
-	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 )
 
-func SetupRepo() {
-	repo := internal.GetRepoOrDie()
+// labelSpec describes a label gt relies on
+type labelSpec struct {
+	name  string
+	color string
+	desc  string
+}
+
+var requiredLabels = []labelSpec{
+	{"inbox", "d4c5f9", "Newly created tasks"},
+	{"active", "0e8a16", "Currently working on"},
+	{"P0", "d93f0b", "Critical priority"},
+	{"P1", "ff9800", "Important priority"},
+	{"P2", "ffeb3b", "Normal priority"},
+	{"P3", "cccccc", "Low priority"},
+	{"review", "1d76db", "Waiting for review (gt pr)"},
+	{"trashed", "5c5c5c", "Removed with gt rm (gt trash restore)"},
+}
 
-	fmt.Printf("Setting up labels for %s...\n", repo)
+func SetupRepo(args []string) {
+	dryRun, _ := ParseDryRunFlag(args)
+	b := openChangeBackendOrDie(dryRun)
 
-	requiredLabels := []struct {
-		name  string
-		color string
-		desc  string
-	}{
-		{"inbox", "d4c5f9", "Newly created tasks"},
-		{"active", "0e8a16", "Currently working on"},
-		{"P0", "d93f0b", "Critical priority"},
-		{"P1", "ff9800", "Important priority"},
-		{"P2", "ffeb3b", "Normal priority"},
-		{"P3", "cccccc", "Low priority"},
-	}
+	fmt.Println("Setting up labels...")
 
-	existingLabels := getExistingLabels(repo)
+	existingLabels := getExistingLabels(b)
 	created := 0
 	skipped := 0
 
@@ -36,8 +40,10 @@ func SetupRepo() {
 			fmt.Printf("  ✓ %s (already exists)\n", label.name)
 			skipped++
 		} else {
-			if createLabel(repo, label.name, label.color, label.desc) {
-				fmt.Printf("  ✓ %s (created)\n", label.name)
+			if err := b.CreateLabel(label.name, label.color, label.desc); err == nil {
+				if !dryRun {
+					fmt.Printf("  ✓ %s (created)\n", label.name)
+				}
 				created++
 			} else {
 				fmt.Printf("  ✗ %s (failed)\n", label.name)
@@ -45,28 +51,19 @@ func SetupRepo() {
 		}
 	}
 
+	if dryRun {
+		fmt.Printf("\nDry run: %d to create, %d already exist\n", created, skipped)
+		return
+	}
 	fmt.Printf("\nSetup complete: %d created, %d already existed\n", created, skipped)
 }
 
-func getExistingLabels(repo string) []string {
-	cmd := exec.Command("gh", "label", "list", "--repo", repo, "--json", "name")
-	output, err := cmd.Output()
+func getExistingLabels(b backend.Backend) []string {
+	labels, err := b.Labels()
 	if err != nil {
 		return []string{}
 	}
-
-	var labels []struct {
-		Name string `json:"name"`
-	}
-	if err := json.Unmarshal(output, &labels); err != nil {
-		return []string{}
-	}
-
-	names := make([]string, len(labels))
-	for i, label := range labels {
-		names[i] = label.Name
-	}
-	return names
+	return labels
 }
 
 func labelExists(labels []string, name string) bool {
@@ -77,13 +74,3 @@ func labelExists(labels []string, name string) bool {
 	}
 	return false
 }
-
-func createLabel(repo, name, color, description string) bool {
-	cmd := exec.Command("gh", "label", "create", name,
-		"--repo", repo,
-		"--color", color,
-		"--description", description)
-
-	err := cmd.Run()
-	return err == nil
-}
@@ -3,30 +3,42 @@ package commands
 import (
 	"fmt"
 	"os"
-	"os/exec"
 
-	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
 )
 
+// StartIssue marks issues as active. With --branch (or the branch setting)
+// it also switches to the issue's branch, see branch-template.
 func StartIssue(args []string) {
-	issueNum, err := ParseIssueNumber(args, "start")
-	if err != nil {
-		fmt.Fprintln(os.Stderr, "Error:", err)
+	dryRun, args := ParseDryRunFlag(args)
+	branch, args := parseBranchFlag(args)
+	b := openChangeBackendOrDie(dryRun)
+	numbers := resolveIssueRefsOrDie(b, args, "start")
+
+	if branch && len(numbers) > 1 {
+		fmt.Fprintln(os.Stderr, "Error: --branch needs a single issue")
 		os.Exit(1)
 	}
 
-	repo := internal.GetRepoOrDie()
-
-	cmd := exec.Command("gh", "issue", "edit", issueNum,
-		"--repo", repo,
-		"--add-label", "active")
+	runBulk(numbers, "activating", func(number int) (string, error) {
+		if err := b.Update(number, backend.Update{AddLabels: []string{"active"}}); err != nil {
+			return "", err
+		}
+		if dryRun {
+			return "", nil
+		}
+		return fmt.Sprintf("Activated #%d", number), nil
+	})
 
-	output, err := cmd.CombinedOutput()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error activating issue: %v\n", err)
-		fmt.Fprintf(os.Stderr, "%s\n", output)
-		os.Exit(1)
+	if branch {
+		issue, err := b.Get(numbers[0])
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
+			os.Exit(1)
+		}
+		if err := checkoutIssueBranch(issue, dryRun); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			os.Exit(1)
+		}
 	}
-
-	fmt.Printf("✓ Activated #%s\n", issueNum)
 }
@@ -0,0 +1,38 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const defaultSyncRemote = "origin"
+
+// SyncTasks exchanges tasks with collaborators for backends that store them locally
+func SyncTasks(args []string) {
+	remote := defaultSyncRemote
+	if len(args) > 0 {
+		remote = args[0]
+	}
+
+	b := OpenBackendOrDie()
+
+	syncer, ok := b.(backend.Syncer)
+	if !ok {
+		fmt.Println("Nothing to sync: this backend is always up to date")
+		return
+	}
+
+	result, err := syncer.Sync(remote)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error syncing tasks: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("✓ Synced with %s: %d new, %d updated, %d merged\n",
+		remote, result.Fetched, result.Updated, result.Merged)
+	for _, change := range result.Renumbered {
+		fmt.Printf("  #%d is now #%d: a pushed task already had its number\n", change.From, change.To)
+	}
+}
@@ -0,0 +1,350 @@
+package commands
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+const (
+	// Permissions for the rendered checklist and its sync snapshot
+	syncMdFilePerms = 0644
+
+	// Priority for new lines written outside of any priority heading
+	syncMdDefaultPriority = "P2"
+
+	// Length of the hashed file path used to name snapshots
+	syncMdSnapshotKeyLength = 16
+)
+
+var (
+	syncMdHeadingRe = regexp.MustCompile(`^#{1,6}\s*(P[0-3])\b`)
+	syncMdTaskRe    = regexp.MustCompile(`^\s*[-*]\s+\[([ xX])\]\s+(.*?)\s*$`)
+	syncMdNumberRe  = regexp.MustCompile(`\s*\(#(\d+)\)$`)
+	syncMdCommentRe = regexp.MustCompile(`\s*<!--.*?-->`)
+)
+
+// syncMdEntry is an issue as last written to (or read from) the checklist
+type syncMdEntry struct {
+	Title    string `json:"title"`
+	Priority string `json:"priority"`
+	Done     bool   `json:"done,omitempty"`
+}
+
+// syncMdLine is a checklist line; number is 0 for lines describing new tasks
+type syncMdLine struct {
+	number int
+	entry  syncMdEntry
+}
+
+// syncMdConflict is a local edit that was not applied because the issue also
+// changed remotely since the last sync
+type syncMdConflict struct {
+	line   syncMdLine
+	reason string
+}
+
+// SyncMarkdown renders open issues as a checklist grouped by priority and,
+// on later runs, applies the edits made to it: checked boxes close issues,
+// new lines create issues, edited titles rename and lines moved under another
+// priority heading reprioritize. Edits to issues that also changed remotely
+// since the last sync are reported and kept in the file instead of applied.
+func SyncMarkdown(args []string) {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, "Error: file required")
+		fmt.Fprintln(os.Stderr, "Usage: gt sync-md <file.md>")
+		os.Exit(1)
+	}
+	path := args[0]
+
+	b := openChangeBackendOrDie(false)
+
+	snapshotPath, err := syncMdSnapshotPath(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	base := loadSyncMdSnapshot(snapshotPath)
+
+	var lines []syncMdLine
+	if content, err := os.ReadFile(path); err == nil {
+		lines = parseSyncMd(string(content))
+	} else if !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	openIssues, err := b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	remote := map[int]internal.Issue{}
+	for _, issue := range openIssues {
+		remote[issue.Number] = issue
+	}
+
+	var conflicts []syncMdConflict
+	for _, line := range lines {
+		if line.number == 0 {
+			createFromSyncMd(b, line)
+			continue
+		}
+
+		issue, found := remote[line.number]
+		if !found {
+			// Not open anymore: closed or deleted since the last sync
+			if fetched, err := b.Get(line.number); err == nil {
+				issue, found = fetched, true
+			}
+		}
+
+		if conflict := applySyncMdLine(b, line, base, issue, found); conflict != nil {
+			conflicts = append(conflicts, *conflict)
+		}
+	}
+
+	// Re-read so the file reflects the applied changes and remote edits
+	openIssues, err = b.List(backend.ListOpen)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	sortIssues(openIssues)
+
+	if err := os.WriteFile(path, []byte(renderSyncMd(openIssues, conflicts)), syncMdFilePerms); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing %s: %v\n", path, err)
+		os.Exit(1)
+	}
+
+	snapshot := map[int]syncMdEntry{}
+	for _, issue := range openIssues {
+		snapshot[issue.Number] = syncMdEntryFromIssue(issue)
+	}
+	if err := saveSyncMdSnapshot(snapshotPath, snapshot); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving sync state: %v\n", err)
+		os.Exit(1)
+	}
+
+	for _, conflict := range conflicts {
+		fmt.Fprintf(os.Stderr, "✗ Conflict #%d: %s\n", conflict.line.number, conflict.reason)
+	}
+	if len(conflicts) > 0 {
+		fmt.Fprintf(os.Stderr, "Your lines were kept in %s; edit them or run sync-md again to apply your version\n", path)
+	}
+
+	fmt.Printf("✓ Synced %d open issues to %s\n", len(openIssues), path)
+}
+
+// applySyncMdLine pushes the local edits of one line to the backend. Each
+// field is compared against the last synced version to tell local and
+// remote changes apart; a conflict is returned when both sides changed it.
+func applySyncMdLine(b backend.Backend, line syncMdLine, base map[int]syncMdEntry, issue internal.Issue, found bool) *syncMdConflict {
+	local := line.entry
+	last, synced := base[line.number]
+	if !synced {
+		// Never synced (e.g. line written by hand): treat the remote as the base
+		if !found {
+			return &syncMdConflict{line, "issue not found"}
+		}
+		last = syncMdEntryFromIssue(issue)
+	}
+
+	titleChanged := local.Title != last.Title
+	priorityChanged := local.Priority != last.Priority
+	if !titleChanged && !priorityChanged && !local.Done {
+		return nil
+	}
+
+	if !found {
+		return &syncMdConflict{line, "edited locally but deleted remotely"}
+	}
+
+	current := syncMdEntryFromIssue(issue)
+	current.Done = issue.State == internal.StateClosed
+
+	var reasons []string
+	if titleChanged && current.Title != last.Title && current.Title != local.Title {
+		reasons = append(reasons, fmt.Sprintf("title changed remotely to %q", current.Title))
+		titleChanged = false
+	}
+	if priorityChanged && current.Priority != last.Priority && current.Priority != local.Priority {
+		reasons = append(reasons, fmt.Sprintf("priority changed remotely to %s", current.Priority))
+		priorityChanged = false
+	}
+	if len(reasons) > 0 {
+		return &syncMdConflict{line, strings.Join(reasons, ", ")}
+	}
+
+	var update backend.Update
+	if titleChanged && current.Title != local.Title {
+		update.Title = &local.Title
+	}
+	if priorityChanged && current.Priority != local.Priority {
+		update.AddLabels = []string{local.Priority}
+		update.RemoveLabels = []string{current.Priority}
+	}
+	if update.Title != nil || len(update.AddLabels) > 0 {
+		if err := b.Update(line.number, update); err != nil {
+			return &syncMdConflict{line, fmt.Sprintf("update failed: %v", err)}
+		}
+		fmt.Printf("✓ Updated #%d: %s [%s]\n", line.number, local.Title, local.Priority)
+	}
+
+	if local.Done && !current.Done {
+		if err := b.Close(line.number); err != nil {
+			return &syncMdConflict{line, fmt.Sprintf("close failed: %v", err)}
+		}
+		fmt.Printf("✓ Closed #%d: %s\n", line.number, local.Title)
+	}
+
+	return nil
+}
+
+func createFromSyncMd(b backend.Backend, line syncMdLine) {
+	issue, err := b.Create(line.entry.Title, "", []string{"inbox", line.entry.Priority})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "✗ Failed to create %q: %v\n", line.entry.Title, err)
+		return
+	}
+	fmt.Printf("✓ Created #%d: %s\n", issue.Number, issue.Title)
+
+	if line.entry.Done {
+		if err := b.Close(issue.Number); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to close #%d: %v\n", issue.Number, err)
+		}
+	}
+}
+
+func parseSyncMd(content string) []syncMdLine {
+	var lines []syncMdLine
+	priority := syncMdDefaultPriority
+
+	for _, raw := range strings.Split(content, "\n") {
+		raw = syncMdCommentRe.ReplaceAllString(raw, "")
+
+		if match := syncMdHeadingRe.FindStringSubmatch(raw); match != nil {
+			priority = match[1]
+			continue
+		}
+
+		match := syncMdTaskRe.FindStringSubmatch(raw)
+		if match == nil {
+			continue
+		}
+
+		line := syncMdLine{entry: syncMdEntry{
+			Priority: priority,
+			Done:     match[1] != " ",
+		}}
+
+		text := match[2]
+		if number := syncMdNumberRe.FindStringSubmatch(text); number != nil {
+			line.number, _ = strconv.Atoi(number[1])
+			text = syncMdNumberRe.ReplaceAllString(text, "")
+		}
+
+		line.entry.Title = strings.TrimSpace(text)
+		if line.entry.Title != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines
+}
+
+// renderSyncMd writes open issues (already sorted) under priority headings.
+// Conflicting lines are written back as the user left them, annotated.
+func renderSyncMd(issues []internal.Issue, conflicts []syncMdConflict) string {
+	var sb strings.Builder
+	sb.WriteString("# Tasks\n\n")
+	sb.WriteString("<!-- Managed by gt sync-md. Check a box to close, add \"- [ ] title\" lines to create,\n")
+	sb.WriteString("     edit titles to rename, move lines under another heading to reprioritize. -->\n")
+
+	conflicted := map[int]syncMdConflict{}
+	for _, conflict := range conflicts {
+		conflicted[conflict.line.number] = conflict
+	}
+
+	for _, priority := range []string{"P0", "P1", "P2", "P3"} {
+		var section []string
+
+		for _, conflict := range conflicts {
+			if conflict.line.entry.Priority == priority {
+				section = append(section, formatSyncMdLine(conflict.line.entry, conflict.line.number)+
+					" <!-- conflict: "+strings.ReplaceAll(conflict.reason, "--", "-")+" -->")
+			}
+		}
+
+		for _, issue := range issues {
+			if _, isConflict := conflicted[issue.Number]; isConflict || internal.ExtractPriority(issue) != priority {
+				continue
+			}
+			section = append(section, formatSyncMdLine(syncMdEntryFromIssue(issue), issue.Number))
+		}
+
+		sb.WriteString("\n## " + priority + "\n")
+		if len(section) > 0 {
+			sb.WriteString("\n" + strings.Join(section, "\n") + "\n")
+		}
+	}
+	return sb.String()
+}
+
+func formatSyncMdLine(entry syncMdEntry, number int) string {
+	box := " "
+	if entry.Done {
+		box = "x"
+	}
+	return fmt.Sprintf("- [%s] %s (#%d)", box, entry.Title, number)
+}
+
+func syncMdEntryFromIssue(issue internal.Issue) syncMdEntry {
+	return syncMdEntry{
+		Title:    issue.Title,
+		Priority: internal.ExtractPriority(issue),
+	}
+}
+
+// syncMdSnapshotPath returns where the last synced state of a checklist is
+// kept, keyed by the checklist's absolute path
+func syncMdSnapshotPath(path string) (string, error) {
+	absPath, err := filepath.Abs(path)
+	if err != nil {
+		return "", err
+	}
+
+	dir, err := config.DataDir("sync-md")
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256([]byte(absPath))
+	return filepath.Join(dir, hex.EncodeToString(sum[:])[:syncMdSnapshotKeyLength]+".json"), nil
+}
+
+func loadSyncMdSnapshot(path string) map[int]syncMdEntry {
+	snapshot := map[int]syncMdEntry{}
+	if content, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(content, &snapshot)
+	}
+	return snapshot
+}
+
+func saveSyncMdSnapshot(path string, snapshot map[int]syncMdEntry) error {
+	content, err := json.MarshalIndent(snapshot, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, syncMdFilePerms)
+}
@@ -0,0 +1,150 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+const (
+	// Issue templates of the repository, as used by GitHub
+	repoTemplateDir = ".github/ISSUE_TEMPLATE"
+)
+
+// issueTemplate is a markdown issue template. Frontmatter follows GitHub's
+// issue template format (name, about, title, labels, assignees).
+type issueTemplate struct {
+	name      string // file name without .md
+	title     string // display name from frontmatter
+	about     string
+	prefix    string // prepended to issue titles, e.g. "[Bug] "
+	labels    []string
+	assignees []string
+	body      string
+}
+
+// ParseTemplateFlag extracts --template/-t <name> from args
+// Returns (templateName, remainingArgs)
+func ParseTemplateFlag(args []string) (string, []string) {
+	name := ""
+	remaining := []string{}
+
+	for i := 0; i < len(args); i++ {
+		if (args[i] == "--template" || args[i] == "-t") && i+1 < len(args) {
+			name = args[i+1]
+			i++
+		} else {
+			remaining = append(remaining, args[i])
+		}
+	}
+
+	return name, remaining
+}
+
+// findTemplateOrDie returns the template called name (by file name or
+// frontmatter name, case-insensitive), listing the available ones otherwise
+func findTemplateOrDie(name string) issueTemplate {
+	templates := loadTemplates()
+	for _, template := range templates {
+		if strings.EqualFold(template.name, name) || strings.EqualFold(template.title, name) {
+			return template
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Error: no issue template named %q\n", name)
+	if len(templates) == 0 {
+		fmt.Fprintf(os.Stderr, "Add templates to %s or to the directory set by ghtask.template-dir\n", repoTemplateDir)
+	} else {
+		fmt.Fprintln(os.Stderr, "Available templates:")
+		for _, template := range templates {
+			fmt.Fprintf(os.Stderr, "  %-12s %s\n", template.name, template.about)
+		}
+	}
+	os.Exit(1)
+	return issueTemplate{}
+}
+
+// loadTemplates reads the templates of the repository and of the
+// template-dir setting; repository templates win on name clashes
+func loadTemplates() []issueTemplate {
+	var dirs []string
+	if output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output(); err == nil {
+		dirs = append(dirs, filepath.Join(strings.TrimSpace(string(output)), repoTemplateDir))
+	}
+	if dir := config.Get("template-dir"); dir != "" {
+		if home, err := os.UserHomeDir(); err == nil && strings.HasPrefix(dir, "~/") {
+			dir = filepath.Join(home, dir[2:])
+		}
+		dirs = append(dirs, dir)
+	}
+
+	var templates []issueTemplate
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		paths, _ := filepath.Glob(filepath.Join(dir, "*.md"))
+		sort.Strings(paths)
+		for _, path := range paths {
+			template, err := readTemplate(path)
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: skipping template %s: %v\n", path, err)
+				continue
+			}
+			if !seen[strings.ToLower(template.name)] {
+				seen[strings.ToLower(template.name)] = true
+				templates = append(templates, template)
+			}
+		}
+	}
+	return templates
+}
+
+func readTemplate(path string) (issueTemplate, error) {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return issueTemplate{}, err
+	}
+
+	fields, body := internal.ParseFrontmatter(string(content))
+	return issueTemplate{
+		name:      strings.TrimSuffix(filepath.Base(path), ".md"),
+		title:     fields["name"],
+		about:     fields["about"],
+		prefix:    fields["title"],
+		labels:    internal.ParseFrontmatterList(fields["labels"]),
+		assignees: internal.ParseFrontmatterList(fields["assignees"]),
+		body:      strings.TrimSpace(body),
+	}, nil
+}
+
+// expandTemplate fills the {{title}}, {{priority}}, {{branch}}, {{user}} and
+// {{date}} variables of text. The user is only looked up when used.
+func expandTemplate(text, title, priority string) string {
+	if !strings.Contains(text, "{{") {
+		return text
+	}
+
+	branch := ""
+	if output, err := exec.Command("git", "symbolic-ref", "--quiet", "--short", "HEAD").Output(); err == nil {
+		branch = strings.TrimSpace(string(output))
+	}
+
+	user := ""
+	if strings.Contains(text, "{{user}}") {
+		user = currentUser()
+	}
+
+	return strings.NewReplacer(
+		"{{title}}", title,
+		"{{priority}}", priority,
+		"{{branch}}", branch,
+		"{{user}}", user,
+		"{{date}}", time.Now().Format("2006-01-02"),
+	).Replace(text)
+}
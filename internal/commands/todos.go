@@ -0,0 +1,386 @@
+package commands
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/github"
+)
+
+const (
+	// Files larger than this are skipped when scanning for comments
+	maxTodoFileSize = 1 << 20
+
+	// Bytes inspected to detect binary files
+	binarySniffLength = 8000
+
+	// Length of the hex key identifying a comment in the mapping
+	todoKeyLength = 12
+
+	// Hidden marker carrying the comment key in the body of its issue
+	todoMarkerFormat = "<!-- gt-todo: %s -->"
+
+	// Permissions for the todo mapping file
+	todoMappingPerms = 0644
+
+	// Priority for comments without a (P0)-(P3) marker
+	todoDefaultPriority = "P2"
+)
+
+// todoRe matches TODO/FIXME/XXX after a comment marker, with an optional
+// parenthesized note that may carry a priority: TODO(P1): fix this. The
+// marker starts the line or follows a blank, so headings (## TODO) and
+// paths (a/TODO) are not comments.
+var todoRe = regexp.MustCompile(`(?:(?:^|\s)(?://+|#|/\*+|--|;+|<!--)|^\s*\*)\s*\b(TODO|FIXME|XXX)\b(?:\(([^)]*)\))?:?\s*(.*)`)
+
+var todoPriorityRe = regexp.MustCompile(`\bP[0-3]\b`)
+
+var todoMarkerRe = regexp.MustCompile(`<!-- gt-todo: ([0-9a-f]+) -->`)
+
+// todoComment is a TODO/FIXME/XXX comment found in the working tree
+type todoComment struct {
+	key      string
+	path     string
+	line     int
+	kind     string
+	priority string
+	text     string
+	source   string
+}
+
+// todoMapping links a comment key to the issue created for it
+type todoMapping struct {
+	Number int    `json:"number"`
+	Path   string `json:"path"`
+	Text   string `json:"text"`
+}
+
+// ScanTodos creates issues for new TODO/FIXME/XXX comments in the working tree
+// and offers to close the issues of comments that were removed. Comments are
+// identified by file and text (not line), so runs are idempotent and moving
+// a comment within its file keeps its issue. Each issue carries its comment's
+// key in a hidden marker, so collaborators and fresh clones find the issues
+// others created; the local mapping only caches them.
+func ScanTodos() {
+	root, err := gitToplevel()
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	store := OpenBackendOrDie()
+	b := withJournal(store)
+
+	mappingPath, err := repoStatePath("todos", ".json")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+	mapping := loadTodoMapping(mappingPath)
+
+	marked, err := markedTodoIssues(store)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+	for key, entry := range marked {
+		if cached, ok := mapping[key]; ok && cached.Number == entry.Number {
+			entry.Path = cached.Path
+		}
+		mapping[key] = entry
+	}
+
+	comments, err := scanTodoComments(root)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error scanning files: %v\n", err)
+		os.Exit(1)
+	}
+
+	links := newTodoLinks(store, root)
+	found := map[string]bool{}
+	created := 0
+	for _, comment := range comments {
+		found[comment.key] = true
+		if _, known := mapping[comment.key]; known {
+			continue
+		}
+
+		issue, err := b.Create(comment.text, todoIssueBody(links, comment), []string{"inbox", comment.priority})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to create issue for %s:%d: %v\n", comment.path, comment.line, err)
+			continue
+		}
+
+		mapping[comment.key] = todoMapping{Number: issue.Number, Path: comment.path, Text: comment.text}
+		fmt.Printf("✓ Created #%d: %s (%s:%d)\n", issue.Number, comment.text, comment.path, comment.line)
+		created++
+	}
+
+	closed := closeRemovedTodos(b, mapping, found)
+
+	if err := saveTodoMapping(mappingPath, mapping); err != nil {
+		fmt.Fprintf(os.Stderr, "Error saving todo mapping: %v\n", err)
+		os.Exit(1)
+	}
+
+	fmt.Printf("\n%d comments found: %d new issues, %d closed\n", len(comments), created, closed)
+}
+
+// closeRemovedTodos handles mapped comments that no longer exist. Issues that
+// are already closed are forgotten; open ones are closed after confirmation.
+func closeRemovedTodos(b backend.Backend, mapping map[string]todoMapping, found map[string]bool) int {
+	var removed []string
+	for key := range mapping {
+		if !found[key] {
+			removed = append(removed, key)
+		}
+	}
+	sort.Slice(removed, func(i, j int) bool {
+		return mapping[removed[i]].Number < mapping[removed[j]].Number
+	})
+
+	closed := 0
+	for _, key := range removed {
+		entry := mapping[key]
+
+		issue, err := b.Get(entry.Number)
+		if err != nil || issue.State == internal.StateClosed {
+			delete(mapping, key)
+			continue
+		}
+
+		if !isInteractive() {
+			fmt.Printf("Comment removed for #%d: %s (run interactively to close it)\n", entry.Number, entry.Text)
+			continue
+		}
+
+		where := entry.Path
+		if where == "" {
+			where = "the code" // marked by a collaborator
+		}
+		if !confirm(fmt.Sprintf("Comment for #%d was removed from %s: %q. Close the issue?", entry.Number, where, entry.Text)) {
+			continue
+		}
+
+		if err := b.Close(entry.Number); err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Failed to close #%d: %v\n", entry.Number, err)
+			continue
+		}
+		delete(mapping, key)
+		fmt.Printf("✓ Closed #%d: %s\n", entry.Number, entry.Text)
+		closed++
+	}
+	return closed
+}
+
+// scanTodoComments reads every tracked and untracked (non-ignored) file
+func scanTodoComments(root string) ([]todoComment, error) {
+	output, err := exec.Command("git", "-C", root, "ls-files", "-z",
+		"--cached", "--others", "--exclude-standard").Output()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list files: %w", err)
+	}
+
+	var comments []todoComment
+	for _, path := range strings.Split(string(output), "\x00") {
+		if path == "" || strings.HasPrefix(path, backend.MarkdownDir+"/") {
+			continue // task files quote the comments they were created from
+		}
+
+		fullPath := filepath.Join(root, filepath.FromSlash(path))
+		info, err := os.Stat(fullPath)
+		if err != nil || info.IsDir() || info.Size() > maxTodoFileSize {
+			continue // deleted, submodule or too large
+		}
+
+		content, err := os.ReadFile(fullPath)
+		if err != nil || bytes.IndexByte(content[:min(len(content), binarySniffLength)], 0) >= 0 {
+			continue
+		}
+
+		comments = append(comments, findTodoComments(path, string(content))...)
+	}
+	return comments, nil
+}
+
+func findTodoComments(path, content string) []todoComment {
+	var comments []todoComment
+	seen := map[string]int{}
+
+	for i, line := range strings.Split(content, "\n") {
+		// Markers in string literals ("// TODO" in help or test text) are
+		// matched with the literals blanked out
+		loc := todoRe.FindStringSubmatchIndex(maskQuoted(line))
+		if loc == nil {
+			continue
+		}
+		match := make([]string, len(loc)/2)
+		for g := range match {
+			if loc[2*g] >= 0 {
+				match[g] = line[loc[2*g]:loc[2*g+1]]
+			}
+		}
+
+		text := strings.TrimSpace(strings.TrimSuffix(strings.TrimSpace(match[3]), "*/"))
+		text = strings.TrimSpace(strings.TrimSuffix(text, "-->"))
+		if text == "" {
+			continue
+		}
+
+		priority := todoDefaultPriority
+		if marker := todoPriorityRe.FindString(match[2]); marker != "" {
+			priority = marker
+		}
+
+		// Identical comments in the same file are told apart by occurrence
+		identity := path + "\x00" + text
+		seen[identity]++
+		sum := sha256.Sum256([]byte(fmt.Sprintf("%s\x00%d", identity, seen[identity])))
+
+		comments = append(comments, todoComment{
+			key:      hex.EncodeToString(sum[:])[:todoKeyLength],
+			path:     path,
+			line:     i + 1,
+			kind:     match[1],
+			priority: priority,
+			text:     text,
+			source:   strings.TrimSpace(line),
+		})
+	}
+	return comments
+}
+
+// maskQuoted replaces double- and backquoted strings in line with underscores,
+// keeping every other byte at its offset. Single quotes are left alone, as
+// they are more often apostrophes than quotes.
+func maskQuoted(line string) string {
+	masked := []byte(line)
+	var quote byte
+	escaped := false
+	for i := 0; i < len(masked); i++ {
+		c := masked[i]
+		switch {
+		case quote == 0:
+			if c == '"' || c == '`' {
+				quote = c
+				masked[i] = '_'
+			}
+			continue
+		case escaped:
+			escaped = false
+		case c == '\\' && quote == '"':
+			escaped = true
+		case c == quote:
+			quote = 0
+		}
+		masked[i] = '_'
+	}
+	return string(masked)
+}
+
+// todoLinks builds GitHub permalinks to comments. Only files unchanged since
+// HEAD are linked at that commit: a modified or untracked file has other
+// lines, or none at all, there.
+type todoLinks struct {
+	repo   string
+	commit string
+	dirty  map[string]bool
+}
+
+// newTodoLinks returns the permalink source for b, or nil when comments
+// cannot be linked (other backends, no commit yet)
+func newTodoLinks(b backend.Backend, root string) *todoLinks {
+	gh, ok := b.(*backend.GitHub)
+	if !ok {
+		return nil
+	}
+	commit, err := exec.Command("git", "-C", root, "rev-parse", "HEAD").Output()
+	if err != nil {
+		return nil
+	}
+
+	links := &todoLinks{repo: gh.Repo, commit: strings.TrimSpace(string(commit)), dirty: map[string]bool{}}
+	for _, args := range [][]string{
+		{"diff", "--name-only", "-z", "HEAD"},
+		{"ls-files", "-z", "--others", "--exclude-standard"},
+	} {
+		output, err := exec.Command("git", append([]string{"-C", root}, args...)...).Output()
+		if err != nil {
+			return nil
+		}
+		for _, path := range strings.Split(string(output), "\x00") {
+			links.dirty[path] = true
+		}
+	}
+	return links
+}
+
+// todoIssueBody links back to the comment, as a permalink on GitHub when the
+// file is committed as it is
+func todoIssueBody(links *todoLinks, comment todoComment) string {
+	location := fmt.Sprintf("`%s:%d`", comment.path, comment.line)
+
+	if links != nil && !links.dirty[comment.path] {
+		url := github.BlobURL(links.repo, links.commit, comment.path, comment.line)
+		location = fmt.Sprintf("[%s:%d](%s)", comment.path, comment.line, url)
+	}
+
+	return fmt.Sprintf("%s comment found at %s:\n\n```\n%s\n```\n\n"+todoMarkerFormat+"\n",
+		comment.kind, location, comment.source, comment.key)
+}
+
+// markedTodoIssues maps the comment keys marked in open issue bodies to their
+// issues
+func markedTodoIssues(b backend.Backend) (map[string]todoMapping, error) {
+	list := b.List
+	if lister, ok := b.(backend.BodyLister); ok {
+		list = lister.ListWithBodies
+	}
+	issues, err := list(backend.ListOpen)
+	if err != nil {
+		return nil, err
+	}
+
+	marked := map[string]todoMapping{}
+	for _, issue := range issues {
+		if match := todoMarkerRe.FindStringSubmatch(issue.Body); match != nil {
+			marked[match[1]] = todoMapping{Number: issue.Number, Text: issue.Title}
+		}
+	}
+	return marked, nil
+}
+
+func gitToplevel() (string, error) {
+	output, err := exec.Command("git", "rev-parse", "--show-toplevel").Output()
+	if err != nil {
+		return "", fmt.Errorf("not in a git repository")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+func loadTodoMapping(path string) map[string]todoMapping {
+	mapping := map[string]todoMapping{}
+	if content, err := os.ReadFile(path); err == nil {
+		_ = json.Unmarshal(content, &mapping)
+	}
+	return mapping
+}
+
+func saveTodoMapping(path string, mapping map[string]todoMapping) error {
+	content, err := json.MarshalIndent(mapping, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, content, todoMappingPerms)
+}
@@ -0,0 +1,84 @@
+package commands
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+func TestMarkedTodoIssues(t *testing.T) {
+	b := backend.NewMarkdown(t.TempDir())
+	comments := findTodoComments("main.go", "// TODO: first\n// FIXME: second\n// TODO: closed\n")
+	for _, comment := range comments {
+		if _, err := b.Create(comment.text, todoIssueBody(nil, comment), []string{"inbox"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := b.Create("by hand", "mentions gt-todo but has no marker", nil); err != nil {
+		t.Fatal(err)
+	}
+	if err := b.Close(3); err != nil {
+		t.Fatal(err)
+	}
+
+	// A fresh clone without the local mapping still knows the open issues
+	got, err := markedTodoIssues(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]todoMapping{
+		comments[0].key: {Number: 1, Text: "first"},
+		comments[1].key: {Number: 2, Text: "second"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("markedTodoIssues = %+v, want %+v", got, want)
+	}
+}
+
+func TestFindTodoComments(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want string // "KIND priority: text", empty when no comment is found
+	}{
+		{name: "line comment", line: "x := 1 // TODO: handle errors", want: "TODO P2: handle errors"},
+		{name: "priority note", line: "// FIXME(P1): token expiry", want: "FIXME P1: token expiry"},
+		{name: "note without priority", line: "# XXX(alice) slow path", want: "XXX P2: slow path"},
+		{name: "doc comment", line: "/// TODO: document", want: "TODO P2: document"},
+		{name: "block comment", line: "/* TODO: remove */", want: "TODO P2: remove"},
+		{name: "block continuation", line: " * TODO: second line", want: "TODO P2: second line"},
+		{name: "html comment", line: "<!-- TODO: screenshot -->", want: "TODO P2: screenshot"},
+		{name: "sql comment", line: "SELECT 1; -- TODO: index", want: "TODO P2: index"},
+		{name: "lisp comment", line: ";; TODO: macro", want: "TODO P2: macro"},
+		{name: "after a string", line: `fmt.Println("a") // TODO: b`, want: "TODO P2: b"},
+		{name: "after a quoted marker", line: `s := "// TODO: no" // TODO: yes`, want: "TODO P2: yes"},
+		{name: "quotes in the text", line: `// TODO: say "hi"`, want: `TODO P2: say "hi"`},
+
+		{name: "markdown heading", line: "## TODO list"},
+		{name: "double quoted", line: `want := "// TODO: fix this"`},
+		{name: "backquoted", line: "help := `# TODO comments become issues`"},
+		{name: "escaped quote in string", line: `s := "say \"hi\" // TODO: no"`},
+		{name: "usage text", line: `fmt.Println("gt todos  Create issues from TODO/FIXME comments")`},
+		{name: "no comment marker", line: "TODO: plain text"},
+		{name: "marker inside a word", line: "see a/TODO.md and a#TODO"},
+		{name: "word containing the keyword", line: "// TODOS are tracked elsewhere"},
+		{name: "keyword without text", line: "// TODO"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, comment := range findTodoComments("main.go", tt.line) {
+				got = append(got, comment.kind+" "+comment.priority+": "+comment.text)
+			}
+			var want []string
+			if tt.want != "" {
+				want = []string{tt.want}
+			}
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("findTodoComments(%q) = %q, want %q", tt.line, got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,142 @@
+package commands
+
+import (
+	"fmt"
+	"os"
+	"slices"
+
+	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+)
+
+const (
+	// Label marking issues closed by gt rm in trash mode
+	trashLabel = "trashed"
+)
+
+// TrashCommand manages issues moved to the trash by gt rm:
+//
+//	gt trash [list]                   show trashed issues
+//	gt trash restore <issues...>      reopen them
+//	gt trash empty [--force]          delete them permanently
+func TrashCommand(args []string) {
+	subcommand := "list"
+	if len(args) > 0 {
+		subcommand, args = args[0], args[1:]
+	}
+
+	dryRun, args := ParseDryRunFlag(args)
+	force, args := parseForceFlag(args)
+	b := openChangeBackendOrDie(dryRun)
+
+	trashed := listTrashedOrDie(b)
+
+	switch subcommand {
+	case "list", "ls":
+		if len(trashed) == 0 {
+			fmt.Println("Trash is empty")
+			return
+		}
+		for _, issue := range trashed {
+			fmt.Printf("#%-5d %s  (trashed %s)\n", issue.Number, issue.Title, formatAge(issue.ClosedAt))
+		}
+
+	case "restore":
+		numbers := resolveTrashRefsOrDie(trashed, args)
+		runBulk(numbers, "restoring", func(number int) (string, error) {
+			if err := b.Reopen(number); err != nil {
+				return "", err
+			}
+			if err := b.Update(number, backend.Update{RemoveLabels: []string{trashLabel}}); err != nil {
+				return "", err
+			}
+			if dryRun {
+				return "", nil
+			}
+			return fmt.Sprintf("Restored #%d", number), nil
+		})
+
+	case "empty":
+		if len(trashed) == 0 {
+			fmt.Println("Trash is empty")
+			return
+		}
+		var numbers []int
+		for _, issue := range trashed {
+			numbers = append(numbers, issue.Number)
+		}
+		if !force && !dryRun {
+			confirmDeleteOrDie(b, numbers)
+		}
+		deleteIssues(b, numbers, dryRun)
+
+	default:
+		fmt.Fprintf(os.Stderr, "Error: unknown trash command: %s\n", subcommand)
+		fmt.Fprintln(os.Stderr, "Usage: gt trash [list | restore <issues...> | empty [--force]]")
+		os.Exit(1)
+	}
+}
+
+// trashIssues closes issues and labels them trashed, so gt trash can restore them
+func trashIssues(b backend.Backend, numbers []int, dryRun bool) {
+	ensureLabels(b, []string{trashLabel})
+
+	runBulk(numbers, "trashing", func(number int) (string, error) {
+		message, err := trashIssue(b, number)
+		if dryRun {
+			return "", err
+		}
+		return message, err
+	})
+}
+
+// trashIssue moves one issue to the trash; the trashed label must exist
+func trashIssue(b backend.Backend, number int) (string, error) {
+	issue, err := b.Get(number)
+	if err != nil {
+		return "", err
+	}
+	if err := b.Update(number, backend.Update{AddLabels: []string{trashLabel}}); err != nil {
+		return "", err
+	}
+	if err := b.Close(number); err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("Trashed #%d: %s (gt trash restore %d)", number, issue.Title, number), nil
+}
+
+func listTrashedOrDie(b backend.Backend) []internal.Issue {
+	issues, err := b.List(backend.ListClosed)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error listing issues: %v\n", err)
+		os.Exit(1)
+	}
+
+	var trashed []internal.Issue
+	for _, issue := range issues {
+		if hasLabel(issue, trashLabel) {
+			trashed = append(trashed, issue)
+		}
+	}
+	sortIssues(trashed)
+	return trashed
+}
+
+// resolveTrashRefsOrDie expands issue references like resolveIssueRefsOrDie,
+// with filters and title fragments selecting trashed issues instead of open
+// ones; numbers of issues that are not in the trash are refused
+func resolveTrashRefsOrDie(trashed []internal.Issue, args []string) []int {
+	refs, err := ParseIssueRefs(args, "trash restore")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "Error:", err)
+		os.Exit(1)
+	}
+
+	for _, number := range refs.Numbers {
+		if !slices.ContainsFunc(trashed, func(issue internal.Issue) bool { return issue.Number == number }) {
+			fmt.Fprintf(os.Stderr, "Error: #%d is not in the trash\n", number)
+			os.Exit(1)
+		}
+	}
+	return selectIssueRefsOrDie(trashed, refs, "trashed")
+}
@@ -0,0 +1,345 @@
+package commands
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+)
+
+const (
+	// Commands kept in the operation log
+	maxOplogGroups = 50
+
+	// Log size that triggers dropping the oldest commands
+	maxOplogSize = 1 << 20
+
+	// Commands shown by gt undo --list
+	undoListLength = 10
+
+	oplogFilePerms = 0644
+)
+
+// oplogGroup is the changes made by one gt invocation
+type oplogGroup struct {
+	id      string
+	entries []backend.JournalEntry
+}
+
+// UndoLast reverts the most recent command recorded in the operation log.
+// --list shows the recent commands; --skip forgets the most recent one
+// without reverting it (e.g. when its issues were changed by hand since).
+// Issues changed since the command are only reverted after confirmation, and
+// created issues are deleted like gt rm does (trash mode, confirmation);
+// --force skips both questions.
+func UndoLast(args []string) {
+	dryRun, args := ParseDryRunFlag(args)
+	force, args := parseForceFlag(args)
+	list, skip := false, false
+	for _, arg := range args {
+		switch arg {
+		case "--list", "-l":
+			list = true
+		case "--skip":
+			skip = true
+		default:
+			fmt.Fprintf(os.Stderr, "Error: unknown flag: %s\n", arg)
+			fmt.Fprintln(os.Stderr, "Usage: gt undo [--list | --skip] [--force] [--dry-run]")
+			os.Exit(1)
+		}
+	}
+
+	path, err := repoStatePath("oplog", ".jsonl")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		os.Exit(1)
+	}
+
+	groups, err := readOplog(path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Error reading operation log: %v\n", err)
+		os.Exit(1)
+	}
+	if len(groups) == 0 {
+		fmt.Println("Nothing to undo")
+		return
+	}
+
+	if list {
+		listOplog(groups)
+		return
+	}
+
+	last := groups[len(groups)-1]
+	if skip {
+		command := last.entries[0].Command
+		last.entries = nil
+		if err := writeOplog(path, groups); err != nil {
+			fmt.Fprintf(os.Stderr, "Error writing operation log: %v\n", err)
+			os.Exit(1)
+		}
+		fmt.Printf("Forgot: %s\n", command)
+		return
+	}
+	fmt.Printf("Undoing: %s\n", last.entries[0].Command)
+
+	// Changes made by undo are not journaled; undoing removes them from the log
+	b := OpenBackendOrDie()
+	if dryRun {
+		b = backend.NewDryRun(b, os.Stdout)
+	}
+
+	if !force && !dryRun {
+		confirmUndoOrDie(b, last.entries)
+	}
+
+	trash := config.GetBool("trash")
+	if trash && slices.ContainsFunc(last.entries, isCreateEntry) {
+		ensureLabels(b, []string{trashLabel})
+	}
+
+	var remaining []backend.JournalEntry
+	for i := len(last.entries) - 1; i >= 0; i-- {
+		entry := last.entries[i]
+		number := entry.Number
+		var message string
+		var err error
+		if isCreateEntry(entry) && trash {
+			message, err = trashIssue(b, entry.Number)
+		} else {
+			number, message, err = entry.Revert(b)
+		}
+		if number != entry.Number && number != 0 && !dryRun {
+			renumberOplog(groups, entry.Number, number)
+		}
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "✗ Error undoing %s #%d: %v\n", entry.Op, entry.Number, err)
+			remaining = append([]backend.JournalEntry{entry}, remaining...)
+			continue
+		}
+		if !dryRun {
+			fmt.Printf("✓ %s\n", message)
+		}
+	}
+	if dryRun {
+		return
+	}
+
+	last.entries = remaining
+	if err := writeOplog(path, groups); err != nil {
+		fmt.Fprintf(os.Stderr, "Error writing operation log: %v\n", err)
+		os.Exit(1)
+	}
+	if len(remaining) > 0 {
+		fmt.Fprintln(os.Stderr, "Run gt undo again to retry, or gt undo --skip to forget this command")
+		os.Exit(1)
+	}
+}
+
+// confirmUndoOrDie asks before overwriting changes made to the command's
+// issues since it ran, and before deleting the issues it created (unless
+// they go to the trash). Without a terminal gt refuses instead.
+func confirmUndoOrDie(b backend.Backend, entries []backend.JournalEntry) {
+	// The last entry of each issue holds the state it should still be in
+	latest := map[int]backend.JournalEntry{}
+	var numbers []int
+	for _, entry := range entries {
+		if _, seen := latest[entry.Number]; !seen {
+			numbers = append(numbers, entry.Number)
+		}
+		latest[entry.Number] = entry
+	}
+
+	var changed []string
+	for _, number := range numbers {
+		entry := latest[number]
+		if entry.After == nil {
+			continue
+		}
+		current, err := b.Get(number)
+		if err != nil {
+			changed = append(changed, fmt.Sprintf("#%d %s: %v", number, entry.Title, err))
+			continue
+		}
+		if fields := entry.ChangedSince(current); len(fields) > 0 {
+			changed = append(changed, fmt.Sprintf("#%d %s: %s changed since", number, entry.Title, strings.Join(fields, ", ")))
+		}
+	}
+
+	if len(changed) > 0 {
+		fmt.Println("These issues were changed after the command:")
+		for _, line := range changed {
+			fmt.Printf("  %s\n", line)
+		}
+		if !isInteractive() {
+			fmt.Fprintln(os.Stderr, "Error: refusing to overwrite those changes (not a terminal); use --force or gt undo --skip")
+			os.Exit(1)
+		}
+		if !confirm("Undo anyway, overwriting those changes?") {
+			fmt.Println("Aborted")
+			os.Exit(1)
+		}
+	}
+
+	var created []int
+	for _, entry := range entries {
+		if isCreateEntry(entry) {
+			created = append(created, entry.Number)
+		}
+	}
+	if len(created) > 0 && !config.GetBool("trash") {
+		confirmDeleteOrDie(b, created)
+	}
+}
+
+func isCreateEntry(entry backend.JournalEntry) bool {
+	return entry.Op == backend.JournalCreate
+}
+
+// renumberOplog points the entries of a recreated issue at its new number
+func renumberOplog(groups []*oplogGroup, old, number int) {
+	for _, group := range groups {
+		for i := range group.entries {
+			if group.entries[i].Number == old {
+				group.entries[i].Number = number
+			}
+		}
+	}
+}
+
+// withJournal records the changes made through b in the current repository's
+// operation log, grouped per gt invocation
+func withJournal(b backend.Backend) backend.Backend {
+	path, err := repoStatePath("oplog", ".jsonl")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: changes will not be undoable: %v\n", err)
+		return b
+	}
+
+	group := fmt.Sprintf("%d-%d", time.Now().UnixNano(), os.Getpid())
+	command := strings.Join(append([]string{filepath.Base(os.Args[0])}, os.Args[1:]...), " ")
+
+	return backend.NewJournal(b, group, command, func(entry backend.JournalEntry) {
+		if err := appendOplog(path, entry); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to record change for undo: %v\n", err)
+		}
+	})
+}
+
+func listOplog(groups []*oplogGroup) {
+	fmt.Println("Recent changes (most recent first, `gt undo` reverts the first):")
+	for i := len(groups) - 1; i >= 0 && i >= len(groups)-undoListLength; i-- {
+		entries := groups[i].entries
+
+		when := entries[0].Time
+		if parsed, err := time.Parse(time.RFC3339, when); err == nil {
+			when = parsed.Local().Format("2006-01-02 15:04")
+		}
+
+		var ops []string
+		for _, entry := range entries {
+			ops = append(ops, fmt.Sprintf("%s #%d", entry.Op, entry.Number))
+		}
+		fmt.Printf("  %s  %s\n      %s\n", when, entries[0].Command, strings.Join(ops, ", "))
+	}
+}
+
+func appendOplog(path string, entry backend.JournalEntry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_APPEND, oplogFilePerms)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(line, '\n'))
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		return err
+	}
+
+	// Keep the log bounded by dropping the oldest commands now and then
+	if info, err := os.Stat(path); err == nil && info.Size() > maxOplogSize {
+		groups, err := readOplog(path)
+		if err != nil {
+			return err
+		}
+		return writeOplog(path, groups)
+	}
+	return nil
+}
+
+// readOplog reads the log as commands in the order they were run
+func readOplog(path string) ([]*oplogGroup, error) {
+	file, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxImportLineSize)
+
+	var groups []*oplogGroup
+	byID := map[string]*oplogGroup{}
+	for scanner.Scan() {
+		var entry backend.JournalEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			continue // skip lines cut short by a crash
+		}
+
+		group, ok := byID[entry.Group]
+		if !ok {
+			group = &oplogGroup{id: entry.Group}
+			byID[entry.Group] = group
+			groups = append(groups, group)
+		}
+		group.entries = append(group.entries, entry)
+	}
+	return groups, scanner.Err()
+}
+
+// writeOplog replaces the log with the most recent non-empty groups
+func writeOplog(path string, groups []*oplogGroup) error {
+	var kept []*oplogGroup
+	for _, group := range groups {
+		if len(group.entries) > 0 {
+			kept = append(kept, group)
+		}
+	}
+	if len(kept) > maxOplogGroups {
+		kept = kept[len(kept)-maxOplogGroups:]
+	}
+
+	var sb strings.Builder
+	for _, group := range kept {
+		for _, entry := range group.entries {
+			line, err := json.Marshal(entry)
+			if err != nil {
+				return err
+			}
+			sb.Write(line)
+			sb.WriteString("\n")
+		}
+	}
+
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, []byte(sb.String()), oplogFilePerms); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
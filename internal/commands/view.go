@@ -1,57 +1,107 @@
 package commands
 
 import (
-	"encoding/json"
 	"fmt"
 	"os"
 	"os/exec"
+	"strings"
 
 	"github.com/DeprecatedLuar/ghtask/internal"
+	"github.com/DeprecatedLuar/ghtask/internal/backend"
+	"github.com/DeprecatedLuar/ghtask/internal/config"
+	"golang.org/x/term"
+)
+
+const (
+	// Commits listed under "Linked work", most recent first
+	maxLinkedCommits = 20
 )
 
 func ViewIssue(args []string) {
-	issueNum, err := ParseIssueNumber(args, "view")
+	b := OpenBackendOrDie()
+	issueNum := resolveIssueRefOrDie(b, args, "view")
+
+	issue, err := b.Get(issueNum)
 	if err != nil {
-		fmt.Fprintln(os.Stderr, err)
+		fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
 		os.Exit(1)
 	}
 
-	repo := internal.GetRepoOrDie()
+	priority := internal.ExtractPriority(issue)
+	color := internal.GetPriorityColor(priority)
+	reset := "\033[0m"
 
-	cmd := exec.Command("gh", "issue", "view", issueNum,
-		"--repo", repo,
-		"--json", "number,title,body,labels")
+	fmt.Printf("%s#%d - %s%s\n\n", color, issue.Number, issue.Title, reset)
+	if issue.Body != "" {
+		fmt.Println(formatBody(issue.Body))
+	}
 
-	output, err := cmd.Output()
-	if err != nil {
-		fmt.Fprintf(os.Stderr, "Error viewing issue: %v\n", err)
-		os.Exit(1)
+	if linked := linkedWork(b, issue.Number); linked != "" {
+		if issue.Body != "" {
+			fmt.Println()
+		}
+		fmt.Print(linked)
 	}
+}
 
-	var viewData struct {
-		Number int              `json:"number"`
-		Title  string           `json:"title"`
-		Body   string           `json:"body"`
-		Labels []internal.Label `json:"labels"`
+// formatBody renders the markdown of an issue body for the terminal; piped
+// output and ghtask.markdown false (or GT_MARKDOWN=0) keep it as written
+func formatBody(body string) string {
+	if !term.IsTerminal(int(os.Stdout.Fd())) {
+		return body
+	}
+	switch strings.ToLower(config.Get("markdown")) {
+	case "false", "no", "off", "0":
+		return body
 	}
+	return internal.RenderMarkdown(body, getTerminalWidth(), internal.SupportsHyperlinks())
+}
 
-	if err := json.Unmarshal(output, &viewData); err != nil {
-		fmt.Fprintf(os.Stderr, "Error parsing issue: %v\n", err)
-		os.Exit(1)
+// linkedWork lists the local commits mentioning the issue and the pull
+// requests linked to it, when the backend knows them
+func linkedWork(b backend.Backend, number int) string {
+	commits := issueCommits(number)
+
+	var prs []backend.LinkedPullRequest
+	if linker, ok := b.(backend.PullRequestLinker); ok {
+		var err error
+		if prs, err = linker.LinkedPullRequests(number); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: failed to fetch linked pull requests: %v\n", err)
+		}
 	}
 
-	issue := internal.Issue{
-		Number: viewData.Number,
-		Title:  viewData.Title,
-		Labels: viewData.Labels,
+	if len(commits) == 0 && len(prs) == 0 {
+		return ""
 	}
 
-	priority := internal.ExtractPriority(issue)
-	color := internal.GetPriorityColor(priority)
-	reset := "\033[0m"
+	dim, reset := "\033[2m", "\033[0m"
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "%sLinked work:%s\n", dim, reset)
+	for _, pr := range prs {
+		fmt.Fprintf(&sb, "  PR #%-5d %s %s(%s)%s\n", pr.Number, pr.Title, dim, strings.ToLower(pr.State), reset)
+	}
+	for _, commit := range commits {
+		fmt.Fprintf(&sb, "  %s\n", commit)
+	}
+	return sb.String()
+}
 
-	fmt.Printf("%s#%d - %s%s\n\n", color, issue.Number, issue.Title, reset)
-	if viewData.Body != "" {
-		fmt.Println(viewData.Body)
+// issueCommits returns "<hash> <subject>" for commits on local branches whose
+// message mentions #number
+func issueCommits(number int) []string {
+	output, err := exec.Command("git", "log", "--branches", "--extended-regexp",
+		fmt.Sprintf("--grep=#%d([^0-9]|$)", number),
+		fmt.Sprintf("--max-count=%d", maxLinkedCommits),
+		"--format=%h %s").Output()
+	if err != nil {
+		return nil // not in a repository, or no commits yet
+	}
+
+	var commits []string
+	for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+		if line != "" {
+			commits = append(commits, line)
+		}
 	}
+	return commits
 }
@@ -0,0 +1,86 @@
+// Package config resolves gt settings. Every setting can be provided through a
+// GT_<KEY> environment variable or through git config under the "ghtask"
+// section, so settings can be scoped per repository or set globally:
+//
+//	git config ghtask.backend git        # this repo only
+//	git config --global ghtask.backend git
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+const (
+	// Permissions for directories created under the data directory
+	dataDirPerms = 0700
+)
+
+// Get returns the value of a setting, or "" when it is not set.
+// Priority: GT_<KEY> env var > git config ghtask.<key>
+func Get(key string) string {
+	if value := os.Getenv(envName(key)); value != "" {
+		return value
+	}
+
+	output, err := exec.Command("git", "config", "--get", "ghtask."+key).Output()
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// GetOr returns the value of a setting, or fallback when it is not set.
+func GetOr(key, fallback string) string {
+	if value := Get(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+// GetBool reports whether a setting is set to a true value (true/yes/on/1).
+func GetBool(key string) bool {
+	switch strings.ToLower(Get(key)) {
+	case "true", "yes", "on", "1":
+		return true
+	}
+	return false
+}
+
+// DataDir returns the directory where gt keeps local data (sync state, logs,
+// drafts), creating subdir inside it if given.
+// Priority: data-dir setting > $XDG_DATA_HOME/ghtask > platform default
+// (~/.local/share/ghtask on Unix, %LOCALAPPDATA%\ghtask on Windows)
+func DataDir(subdir string) (string, error) {
+	dir := Get("data-dir")
+
+	if dir == "" {
+		if xdg := os.Getenv("XDG_DATA_HOME"); xdg != "" {
+			dir = filepath.Join(xdg, "ghtask")
+		} else if runtime.GOOS == "windows" && os.Getenv("LOCALAPPDATA") != "" {
+			dir = filepath.Join(os.Getenv("LOCALAPPDATA"), "ghtask")
+		} else {
+			home, err := os.UserHomeDir()
+			if err != nil {
+				return "", fmt.Errorf("could not determine data directory: %w", err)
+			}
+			dir = filepath.Join(home, ".local", "share", "ghtask")
+		}
+	}
+
+	dir = filepath.Join(dir, subdir)
+	if err := os.MkdirAll(dir, dataDirPerms); err != nil {
+		return "", fmt.Errorf("failed to create data directory: %w", err)
+	}
+	return dir, nil
+}
+
+// envName maps a setting key to its environment variable (branch-template → GT_BRANCH_TEMPLATE).
+func envName(key string) string {
+	name := strings.NewReplacer(".", "_", "-", "_").Replace(key)
+	return "GT_" + strings.ToUpper(name)
+}
@@ -6,13 +6,48 @@ import (
 	"fmt"
 	"os"
 	"os/exec"
+	"path/filepath"
 	"runtime"
 	"strings"
+
+	"github.com/DeprecatedLuar/ghtask/internal/config"
 )
 
-// GetEditor returns the user's preferred editor.
-// Priority: $EDITOR env var > platform defaults (vim/nano/vi on Unix, notepad on Windows)
-func GetEditor() string {
+// GUI editors return at once unless told to wait for the file to be closed
+// with one of these flags
+var guiEditorWaitFlags = map[string][]string{
+	"code":          {"--wait", "-w"},
+	"code-insiders": {"--wait", "-w"},
+	"codium":        {"--wait", "-w"},
+	"cursor":        {"--wait", "-w"},
+	"subl":          {"--wait", "-w"},
+	"atom":          {"--wait", "-w"},
+	"zed":           {"--wait", "-w"},
+	"mate":          {"--wait", "-w"},
+	"gedit":         {"--wait", "-w"},
+	"kate":          {"--block", "-b"},
+	"gvim":          {"--nofork", "-f"},
+	"mvim":          {"--nofork", "-f"},
+	"idea":          {"--wait"},
+	"open":          {"--wait-apps", "-W"},
+}
+
+// GetEditor returns the command line of the user's editor for a kind of text
+// (body, title, issue, plan, merge).
+// Priority: editor.<kind> setting > editor setting > $VISUAL > $EDITOR >
+// platform defaults (vim/nano/vi on Unix, notepad on Windows)
+func GetEditor(kind string) string {
+	if kind != "" {
+		if editor := config.Get("editor." + kind); editor != "" {
+			return editor
+		}
+	}
+	if editor := config.Get("editor"); editor != "" {
+		return editor
+	}
+	if editor := os.Getenv("VISUAL"); editor != "" && os.Getenv("TERM") != "dumb" {
+		return editor
+	}
 	if editor := os.Getenv("EDITOR"); editor != "" {
 		return editor
 	}
@@ -34,8 +69,6 @@ func GetEditor() string {
 // If content is empty, creates an empty temp file.
 // Returns the edited content after the editor exits.
 func OpenEditorWithContent(initialContent, fieldName string) (string, error) {
-	editor := GetEditor()
-
 	tmpFile, err := os.CreateTemp("", "ghtask-"+fieldName+"-*.md")
 	if err != nil {
 		return "", fmt.Errorf("failed to create temp file: %w", err)
@@ -53,19 +86,71 @@ func OpenEditorWithContent(initialContent, fieldName string) (string, error) {
 
 	defer os.Remove(tmpPath)
 
-	cmd := exec.Command(editor, tmpPath)
+	if err := EditFile(tmpPath, fieldName); err != nil {
+		return "", err
+	}
+
+	editedContent, err := os.ReadFile(tmpPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read temp file: %w", err)
+	}
+
+	return strings.TrimSpace(string(editedContent)), nil
+}
+
+// EditFile opens path in the user's editor for kind and waits for it to exit.
+// The editor setting may include arguments ("code --wait", "emacsclient -t").
+func EditFile(path, kind string) error {
+	line := GetEditor(kind)
+	words, err := editorWords(line)
+	if err != nil {
+		return fmt.Errorf("invalid editor setting: %w", err)
+	}
+	if len(words) == 0 {
+		return fmt.Errorf("invalid editor setting: %q", line)
+	}
+
+	warnNonBlockingEditor(words)
+
+	cmd := exec.Command(words[0], append(words[1:], path)...)
 	cmd.Stdin = os.Stdin
 	cmd.Stdout = os.Stdout
 	cmd.Stderr = os.Stderr
 
 	if err := cmd.Run(); err != nil {
-		return "", fmt.Errorf("editor exited with error: %w", err)
+		return fmt.Errorf("editor exited with error: %w", err)
 	}
+	return nil
+}
 
-	editedContent, err := os.ReadFile(tmpPath)
-	if err != nil {
-		return "", fmt.Errorf("failed to read temp file: %w", err)
+// warnNonBlockingEditor warns when words start a known GUI editor without the
+// flag that makes it wait. Terminal editors are never flagged, since quitting
+// one right away (vim's :q) is a deliberate way to abort.
+func warnNonBlockingEditor(words []string) {
+	name := strings.TrimSuffix(strings.ToLower(filepath.Base(words[0])), ".exe")
+	flags, isGUI := guiEditorWaitFlags[name]
+	if !isGUI {
+		return
 	}
 
-	return strings.TrimSpace(string(editedContent)), nil
+	for _, word := range words[1:] {
+		for _, flag := range flags {
+			if word == flag {
+				return
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "Warning: %s returns before the file is closed unless started with %s; "+
+		"set EDITOR=\"%s %s\"\n", name, flags[0], strings.Join(words, " "), flags[0])
+}
+
+// editorWords splits the editor setting into a command and its arguments. A
+// setting naming an existing file is used whole, so unquoted paths with spaces
+// (C:\Program Files\Notepad++\notepad++.exe) keep working.
+func editorWords(line string) ([]string, error) {
+	if info, err := os.Stat(line); err == nil && !info.IsDir() {
+		return []string{line}, nil
+	}
+	return SplitShellWords(line)
 }
@@ -0,0 +1,174 @@
+// Package internal provides a minimal YAML frontmatter reader/writer for
+// markdown documents (task files, issue templates, editor documents).
+// Only flat "key: value" fields and string lists are supported.
+package internal
+
+import (
+	"strconv"
+	"strings"
+)
+
+const frontmatterDelimiter = "---"
+
+// FrontmatterField is a single key/value pair, kept in order when formatting
+type FrontmatterField struct {
+	Key   string
+	Value string
+}
+
+// ParseFrontmatter splits a document into its frontmatter fields and body.
+// Block lists ("key:" followed by "- item" lines) are returned in inline
+// form ("[a, b]"). Documents without frontmatter return no fields and the
+// whole content as body.
+func ParseFrontmatter(content string) (map[string]string, string) {
+	fields := map[string]string{}
+
+	lines := strings.Split(strings.ReplaceAll(content, "\r\n", "\n"), "\n")
+	if len(lines) == 0 || strings.TrimSpace(lines[0]) != frontmatterDelimiter {
+		return fields, content
+	}
+
+	end := -1
+	for i := 1; i < len(lines); i++ {
+		if strings.TrimSpace(lines[i]) == frontmatterDelimiter {
+			end = i
+			break
+		}
+	}
+	if end < 0 {
+		return fields, content
+	}
+
+	lastKey := ""
+	var listItems []string
+	flushList := func() {
+		if lastKey != "" && len(listItems) > 0 {
+			fields[lastKey] = FormatFrontmatterList(listItems)
+		}
+		listItems = nil
+	}
+
+	for _, line := range lines[1:end] {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+
+		if item, isItem := strings.CutPrefix(trimmed, "- "); isItem && lastKey != "" {
+			listItems = append(listItems, unquote(strings.TrimSpace(item)))
+			continue
+		}
+
+		key, value, found := strings.Cut(trimmed, ":")
+		if !found {
+			continue
+		}
+		flushList()
+		lastKey = strings.TrimSpace(key)
+		fields[lastKey] = unquote(strings.TrimSpace(value))
+	}
+	flushList()
+
+	body := strings.Join(lines[end+1:], "\n")
+	return fields, strings.TrimLeft(body, "\n")
+}
+
+// FormatFrontmatter renders fields as a frontmatter block followed by body
+func FormatFrontmatter(fields []FrontmatterField, body string) string {
+	var sb strings.Builder
+	sb.WriteString(frontmatterDelimiter + "\n")
+	for _, field := range fields {
+		sb.WriteString(field.Key + ": " + quote(field.Value) + "\n")
+	}
+	sb.WriteString(frontmatterDelimiter + "\n")
+
+	if body != "" {
+		sb.WriteString("\n" + strings.TrimRight(body, "\n") + "\n")
+	}
+	return sb.String()
+}
+
+// ParseFrontmatterList parses a list value written as "[a, b]" or "a, b".
+// Quoted items may contain commas and brackets.
+func ParseFrontmatterList(value string) []string {
+	value = strings.TrimSpace(value)
+	value = strings.TrimSuffix(strings.TrimPrefix(value, "["), "]")
+
+	var items []string
+	for _, item := range splitListItems(value) {
+		if item = unquote(strings.TrimSpace(item)); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// FormatFrontmatterList renders a list value in inline form ("[a, b]"),
+// quoting items that would otherwise be split or misread
+func FormatFrontmatterList(items []string) string {
+	quoted := make([]string, len(items))
+	for i, item := range items {
+		if strings.ContainsAny(item, ",[]{}:") {
+			quoted[i] = strconv.Quote(item)
+		} else {
+			quoted[i] = quote(item)
+		}
+	}
+	return "[" + strings.Join(quoted, ", ") + "]"
+}
+
+// splitListItems splits value on the commas outside quoted items
+func splitListItems(value string) []string {
+	var items []string
+	var quoteChar byte
+	start := 0
+	for i := 0; i < len(value); i++ {
+		switch c := value[i]; {
+		case quoteChar == '"' && c == '\\':
+			i++ // escaped character
+		case quoteChar == '\'' && c == '\'' && i+1 < len(value) && value[i+1] == '\'':
+			i++ // doubled single quote
+		case quoteChar != 0:
+			if c == quoteChar {
+				quoteChar = 0
+			}
+		case c == '"' || c == '\'':
+			if strings.TrimSpace(value[start:i]) == "" {
+				quoteChar = c // only a leading quote opens a quoted item
+			}
+		case c == ',':
+			items = append(items, value[start:i])
+			start = i + 1
+		}
+	}
+	return append(items, value[start:])
+}
+
+// quote wraps values YAML would misread (leading/trailing spaces, special
+// leading characters such as # starting a comment, ": " or " #" inside) in
+// double quotes
+func quote(value string) string {
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		return value // inline list, written as-is
+	}
+	if value != strings.TrimSpace(value) ||
+		strings.ContainsAny(value[:min(1, len(value))], `"'[{}&*!|>%@#`+"`") ||
+		strings.Contains(value, ": ") || strings.Contains(value, " #") {
+		return strconv.Quote(value)
+	}
+	return value
+}
+
+func unquote(value string) string {
+	if len(value) >= 2 {
+		switch {
+		case value[0] == '"' && value[len(value)-1] == '"':
+			if unquoted, err := strconv.Unquote(value); err == nil {
+				return unquoted
+			}
+		case value[0] == '\'' && value[len(value)-1] == '\'':
+			return strings.ReplaceAll(value[1:len(value)-1], "''", "'")
+		}
+	}
+	return value
+}
@@ -0,0 +1,148 @@
+package internal
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFrontmatterRoundTrip(t *testing.T) {
+	values := []string{
+		"plain title",
+		"",
+		"#12 starts like a comment",
+		"ends with a # hash",
+		"key: value inside",
+		" leading space",
+		"trailing space ",
+		`"quoted" start`,
+		"it's fine",
+		"[Bug] crash on save",
+		"[WIP]",
+		"*bold*",
+		"@mention",
+		"`code`",
+		"tab\tand newline\n",
+		"ünïcode ✓",
+	}
+
+	for _, value := range values {
+		t.Run(value, func(t *testing.T) {
+			document := FormatFrontmatter([]FrontmatterField{{Key: "title", Value: value}}, "body")
+			fields, body := ParseFrontmatter(document)
+			if fields["title"] != value {
+				t.Errorf("title %q came back as %q from:\n%s", value, fields["title"], document)
+			}
+			if body != "body\n" {
+				t.Errorf("body = %q, want %q", body, "body\n")
+			}
+		})
+	}
+}
+
+func TestParseFrontmatter(t *testing.T) {
+	tests := []struct {
+		name   string
+		input  string
+		fields map[string]string
+		body   string
+	}{
+		{
+			name:   "no frontmatter",
+			input:  "just text\n",
+			fields: map[string]string{},
+			body:   "just text\n",
+		},
+		{
+			name:   "unterminated",
+			input:  "---\ntitle: x\n",
+			fields: map[string]string{},
+			body:   "---\ntitle: x\n",
+		},
+		{
+			name:   "block list and comments",
+			input:  "---\n# a comment\nname: Bug\nlabels:\n  - bug\n  - 'needs triage'\n---\n\nSteps\n",
+			fields: map[string]string{"name": "Bug", "labels": "[bug, needs triage]"},
+			body:   "Steps\n",
+		},
+		{
+			name:   "single and double quotes",
+			input:  "---\na: 'it''s'\nb: \"say \\\"hi\\\"\"\n---\n",
+			fields: map[string]string{"a": "it's", "b": `say "hi"`},
+			body:   "",
+		},
+		{
+			name:   "windows line endings",
+			input:  "---\r\ntitle: x\r\n---\r\nbody\r\n",
+			fields: map[string]string{"title": "x"},
+			body:   "body\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fields, body := ParseFrontmatter(tt.input)
+			if !reflect.DeepEqual(fields, tt.fields) {
+				t.Errorf("fields = %q, want %q", fields, tt.fields)
+			}
+			if body != tt.body {
+				t.Errorf("body = %q, want %q", body, tt.body)
+			}
+		})
+	}
+}
+
+func TestFrontmatterListRoundTrip(t *testing.T) {
+	tests := [][]string{
+		nil,
+		{"bug"},
+		{"bug", "P1", "good first issue"},
+		{"a, b", "c"},
+		{"[WIP]", "ends]", "[starts"},
+		{"area: api", "scope:ui"},
+		{`"quoted"`, `'single'`, "it's", `say "hi"`},
+		{"# hash", " padded ", "{braces}"},
+		{`back\slash`, "ünïcode ✓"},
+	}
+
+	for _, items := range tests {
+		got := ParseFrontmatterList(FormatFrontmatterList(items))
+		if !reflect.DeepEqual(got, items) {
+			t.Errorf("list %q came back as %q", items, got)
+		}
+	}
+}
+
+func TestParseFrontmatterList(t *testing.T) {
+	tests := []struct {
+		value string
+		want  []string
+	}{
+		{"", nil},
+		{"[]", nil},
+		{"a, b", []string{"a", "b"}},
+		{"[a,b , ,c]", []string{"a", "b", "c"}},
+		{`["a, b", 'c, d', e]`, []string{"a, b", "c, d", "e"}},
+		{`["x]", "[y"]`, []string{"x]", "[y"}},
+		{`["say \"hi, there\""]`, []string{`say "hi, there"`}},
+		{`['it''s, ok']`, []string{"it's, ok"}},
+		{"[it's, fine]", []string{"it's", "fine"}},
+	}
+
+	for _, tt := range tests {
+		if got := ParseFrontmatterList(tt.value); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("ParseFrontmatterList(%q) = %q, want %q", tt.value, got, tt.want)
+		}
+	}
+}
+
+func TestFrontmatterListInDocument(t *testing.T) {
+	labels := []string{"area: api", "a, b", "[WIP]", `"quoted"`}
+	document := FormatFrontmatter([]FrontmatterField{
+		{Key: "labels", Value: FormatFrontmatterList(labels)},
+	}, "")
+
+	fields, _ := ParseFrontmatter(document)
+	if got := ParseFrontmatterList(fields["labels"]); !reflect.DeepEqual(got, labels) {
+		t.Errorf("labels %q came back as %q from:\n%s", labels, got, document)
+	}
+}
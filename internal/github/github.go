@@ -41,3 +41,17 @@ func parseGitHubRepo(url string) (string, error) {
 
 	return "", fmt.Errorf("could not parse GitHub repo from: %s", url)
 }
+
+// BlobURL returns a permalink to a line of a file at the given commit
+func BlobURL(repo, commit, path string, line int) string {
+	return fmt.Sprintf("https://github.com/%s/blob/%s/%s#L%d", repo, commit, path, line)
+}
+
+// CurrentUser returns the login of the account gh is authenticated as
+func CurrentUser() (string, error) {
+	output, err := exec.Command("gh", "api", "user", "--jq", ".login").Output()
+	if err != nil {
+		return "", fmt.Errorf("could not determine the GitHub user (gh auth login?)")
+	}
+	return strings.TrimSpace(string(output)), nil
+}
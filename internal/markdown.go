@@ -0,0 +1,543 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+const (
+	// Markdown colors (ANSI 256-color codes)
+	colorHeading = 75  // Headings - light blue
+	colorLink    = 39  // Links - blue
+	colorCode    = 216 // Inline code and code blocks - light orange
+	colorCodeBg  = 236 // Code background - dark gray
+	colorQuote   = 242 // Block quote bar - gray
+	colorChecked = 71  // Checked task box - green
+
+	// Columns of a code block's left padding; tabs in code expand to tabWidth
+	codePadding = 1
+	tabWidth    = 4
+)
+
+// textStyle is a set of inline styles, combined as text nests (**[link](url)**)
+type textStyle uint16
+
+const (
+	styleBold textStyle = 1 << iota
+	styleItalic
+	styleUnderline
+	styleStrike
+	styleDim
+	styleCode
+	styleLink
+	styleHeading
+)
+
+// span is a run of text sharing a style; links carry their target
+type span struct {
+	text  string
+	style textStyle
+	url   string
+}
+
+var (
+	headingRe = regexp.MustCompile(`^(#{1,6})\s+(.*?)(\s+#+)?\s*$`)
+	ruleRe    = regexp.MustCompile(`^(\*\s*){3,}$|^(-\s*){3,}$|^(_\s*){3,}$`)
+	listRe    = regexp.MustCompile(`^(\s*)([-*+]|\d{1,9}[.)])\s+(.*)$`)
+	taskRe    = regexp.MustCompile(`^\[([ xX])\]\s+`)
+	bareURLRe = regexp.MustCompile(`^https?://[^\s<>]+`)
+)
+
+// Characters a backslash makes literal
+const escapable = "!\"#$%&'()*+,-./:;<=>?@[\\]^_`{|}~"
+
+// Bullets by list depth
+var bullets = []string{"•", "◦", "▪"}
+
+// RenderMarkdown formats GitHub-flavored markdown for a terminal width columns
+// wide: headings, emphasis, lists and task boxes, code blocks, block quotes
+// and links. Links become OSC 8 hyperlinks when hyperlinks is set and are
+// followed by their URL otherwise. Tables and HTML are kept as written.
+func RenderMarkdown(source string, width int, hyperlinks bool) string {
+	r := markdownRenderer{hyperlinks: hyperlinks}
+	lines := strings.Split(strings.ReplaceAll(source, "\r\n", "\n"), "\n")
+	return strings.Join(r.blocks(lines, width), "\n")
+}
+
+// SupportsHyperlinks reports whether the terminal is known to handle OSC 8
+// hyperlinks; others would print the escape sequences or drop the link
+func SupportsHyperlinks() bool {
+	if os.Getenv("TMUX") != "" || strings.HasPrefix(os.Getenv("TERM"), "screen") {
+		return false
+	}
+	switch os.Getenv("TERM_PROGRAM") {
+	case "iTerm.app", "WezTerm", "vscode", "ghostty", "Hyper":
+		return true
+	}
+	if os.Getenv("KITTY_WINDOW_ID") != "" || os.Getenv("WT_SESSION") != "" || os.Getenv("KONSOLE_VERSION") != "" {
+		return true
+	}
+	if version, err := strconv.Atoi(os.Getenv("VTE_VERSION")); err == nil && version >= 5000 {
+		return true
+	}
+	term := os.Getenv("TERM")
+	return strings.Contains(term, "kitty") || strings.Contains(term, "ghostty") ||
+		strings.Contains(term, "foot") || strings.Contains(term, "alacritty")
+}
+
+type markdownRenderer struct {
+	hyperlinks bool
+}
+
+// blocks renders lines block by block (quotes recurse into their content) and
+// returns the output lines, keeping one blank line where the source has some
+func (r *markdownRenderer) blocks(lines []string, width int) []string {
+	var out []string
+	blank := func() {
+		if len(out) > 0 && out[len(out)-1] != "" {
+			out = append(out, "")
+		}
+	}
+
+	for i := 0; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], " \t")
+		trimmed := strings.TrimSpace(line)
+
+		switch {
+		case trimmed == "":
+			blank()
+
+		case strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~"):
+			fence := trimmed[:3]
+			var code []string
+			for i++; i < len(lines) && !strings.HasPrefix(strings.TrimSpace(lines[i]), fence); i++ {
+				code = append(code, lines[i])
+			}
+			out = append(out, r.codeBlock(code, width)...)
+
+		case strings.HasPrefix(trimmed, "<!--"):
+			// Comments, like the hints in issue templates, are hidden as on GitHub
+			for !strings.Contains(lines[i], "-->") && i+1 < len(lines) {
+				i++
+			}
+
+		case strings.HasPrefix(trimmed, ">"):
+			var quoted []string
+			for ; i < len(lines) && strings.HasPrefix(strings.TrimSpace(lines[i]), ">"); i++ {
+				text := strings.TrimPrefix(strings.TrimSpace(lines[i]), ">")
+				quoted = append(quoted, strings.TrimPrefix(text, " "))
+			}
+			i--
+			bar := fmt.Sprintf("\033[38;5;%dm│\033[0m ", colorQuote)
+			for _, text := range r.blocks(quoted, width-2) {
+				out = append(out, bar+text)
+			}
+
+		case headingRe.MatchString(trimmed):
+			m := headingRe.FindStringSubmatch(trimmed)
+			style := styleBold | styleHeading
+			if len(m[1]) == 1 {
+				style |= styleUnderline
+			}
+			out = append(out, wrapSpans(r.inline(m[2], style), width, "", "")...)
+
+		case ruleRe.MatchString(trimmed):
+			out = append(out, fmt.Sprintf("\033[38;5;%dm%s\033[0m", colorQuote, strings.Repeat("─", max(width, 1))))
+
+		case listRe.MatchString(line):
+			out = append(out, r.listItem(listRe.FindStringSubmatch(line), width)...)
+
+		case strings.HasPrefix(trimmed, "|"):
+			out = append(out, line) // pipe tables keep their alignment
+
+		default:
+			// GitHub keeps the line breaks of issue bodies, so each line wraps
+			// on its own; indentation (list item continuations) is kept
+			indent := line[:len(line)-len(strings.TrimLeft(line, " \t"))]
+			out = append(out, wrapSpans(r.inline(trimmed, 0), width, indent, indent)...)
+		}
+	}
+
+	for len(out) > 0 && out[len(out)-1] == "" {
+		out = out[:len(out)-1]
+	}
+	return out
+}
+
+// codeBlock renders code on a background as wide as the terminal; long lines
+// are not wrapped
+func (r *markdownRenderer) codeBlock(code []string, width int) []string {
+	style := fmt.Sprintf("\033[38;5;%d;48;5;%dm", colorCode, colorCodeBg)
+	pad := strings.Repeat(" ", codePadding)
+
+	out := make([]string, 0, len(code))
+	for _, line := range code {
+		line = pad + strings.ReplaceAll(strings.TrimRight(line, " \t"), "\t", strings.Repeat(" ", tabWidth))
+		if n := utf8.RuneCountInString(line); n < width {
+			line += strings.Repeat(" ", width-n)
+		}
+		out = append(out, style+line+"\033[0m")
+	}
+	return out
+}
+
+// listItem renders a list item with its marker, wrapping under the text.
+// m holds the indentation, the marker and the text.
+func (r *markdownRenderer) listItem(m []string, width int) []string {
+	indent, marker, text := strings.ReplaceAll(m[1], "\t", "  "), m[2], m[3]
+
+	ordered := marker[0] >= '0' && marker[0] <= '9'
+	if !ordered {
+		marker = bullets[len(indent)/2%len(bullets)]
+	}
+
+	// Task list items show a box instead of a bullet
+	if task := taskRe.FindStringSubmatch(text); task != nil {
+		text = text[len(task[0]):]
+		box := "☐"
+		if task[1] != " " {
+			box = fmt.Sprintf("\033[38;5;%dm☑\033[0m", colorChecked)
+		}
+		if ordered {
+			marker += " " + box
+		} else {
+			marker = box
+		}
+	}
+
+	first := indent + marker + " "
+	rest := strings.Repeat(" ", visibleWidth(first))
+	return wrapSpans(r.inline(text, 0), width, first, rest)
+}
+
+// inline splits text into styled spans: `code`, **bold**, *italic*,
+// ~~strikethrough~~, [links](url), <autolinks>, bare URLs and images
+func (r *markdownRenderer) inline(text string, style textStyle) []span {
+	var spans []span
+	var plain strings.Builder
+	flush := func() {
+		if plain.Len() > 0 {
+			spans = append(spans, span{text: plain.String(), style: style})
+			plain.Reset()
+		}
+	}
+
+	for i := 0; i < len(text); {
+		c := text[i]
+		switch {
+		case c == '\\' && i+1 < len(text) && strings.IndexByte(escapable, text[i+1]) >= 0:
+			plain.WriteByte(text[i+1])
+			i += 2
+			continue
+
+		case c == '`':
+			n := runLength(text, i)
+			delim := text[i : i+n]
+			if end := strings.Index(text[i+n:], delim); end >= 0 {
+				flush()
+				code := text[i+n : i+n+end]
+				if strings.TrimSpace(code) != "" {
+					code = strings.TrimSpace(code)
+				}
+				spans = append(spans, span{text: code, style: style | styleCode})
+				i += n + end + n
+				continue
+			}
+
+		case c == '!' && strings.HasPrefix(text[i+1:], "["):
+			if label, url, n, ok := parseLink(text[i+1:]); ok {
+				flush()
+				if label != "" {
+					label = ": " + label
+				}
+				spans = append(spans, r.link([]span{{text: "image" + label, style: style | styleLink}}, url, style)...)
+				i += 1 + n
+				continue
+			}
+
+		case c == '[':
+			if label, url, n, ok := parseLink(text[i:]); ok {
+				flush()
+				spans = append(spans, r.link(r.inline(label, style|styleLink), url, style)...)
+				i += n
+				continue
+			}
+
+		case c == '<':
+			if end := strings.IndexByte(text[i:], '>'); end > 0 && bareURLRe.MatchString(text[i+1:i+end]) {
+				flush()
+				url := text[i+1 : i+end]
+				spans = append(spans, r.link([]span{{text: url, style: style | styleLink}}, url, style)...)
+				i += end + 1
+				continue
+			}
+
+		case c == 'h' && (i == 0 || !isWordByte(text[i-1])):
+			if url := bareURLRe.FindString(text[i:]); url != "" {
+				url = strings.TrimRight(url, ".,;:!?)'\"")
+				flush()
+				spans = append(spans, r.link([]span{{text: url, style: style | styleLink}}, url, style)...)
+				i += len(url)
+				continue
+			}
+
+		case c == '*' || c == '_' || c == '~':
+			if inner, emphasis, n, ok := parseEmphasis(text, i); ok {
+				flush()
+				spans = append(spans, r.inline(inner, style|emphasis)...)
+				i += n
+				continue
+			}
+		}
+
+		plain.WriteByte(c)
+		i++
+	}
+
+	flush()
+	return spans
+}
+
+// link links the label spans to url: as an OSC 8 hyperlink when the terminal
+// supports it, otherwise by following the label with the url
+func (r *markdownRenderer) link(label []span, url string, style textStyle) []span {
+	var text strings.Builder
+	for i := range label {
+		if r.hyperlinks {
+			label[i].url = url
+		}
+		text.WriteString(label[i].text)
+	}
+	if !r.hyperlinks && text.String() != url {
+		label = append(label, span{text: " (" + url + ")", style: style | styleDim})
+	}
+	return label
+}
+
+// parseLink parses "[label](url "title")" at the start of s and returns the
+// label, the url and the length of the link
+func parseLink(s string) (string, string, int, bool) {
+	depth := 0
+	for i := 0; i < len(s); i++ {
+		switch s[i] {
+		case '\\':
+			i++
+		case '[':
+			depth++
+		case ']':
+			depth--
+			if depth > 0 {
+				continue
+			}
+			if !strings.HasPrefix(s[i+1:], "(") {
+				return "", "", 0, false
+			}
+			end := strings.IndexByte(s[i+2:], ')')
+			if end < 0 {
+				return "", "", 0, false
+			}
+			target := strings.Fields(s[i+2 : i+2+end])
+			if len(target) == 0 {
+				return "", "", 0, false
+			}
+			url := strings.Trim(target[0], "<>")
+			return s[1:i], url, i + 2 + end + 1, true
+		}
+	}
+	return "", "", 0, false
+}
+
+// parseEmphasis parses *italic*, **bold**, ***both*** (or with _) and
+// ~~strikethrough~~ starting at text[i], returning the inner text, its style
+// and the length of the whole
+func parseEmphasis(text string, i int) (string, textStyle, int, bool) {
+	c := text[i]
+	n := min(runLength(text, i), 3)
+
+	var style textStyle
+	switch {
+	case c == '~':
+		n = min(n, 2)
+		style = styleStrike
+	case n == 1:
+		style = styleItalic
+	case n == 2:
+		style = styleBold
+	default:
+		style = styleBold | styleItalic
+	}
+
+	// Intraword underscores (snake_case) are not emphasis
+	if c == '_' && i > 0 && isWordByte(text[i-1]) {
+		return "", 0, 0, false
+	}
+
+	delim := text[i : i+n]
+	start := i + n
+	if start >= len(text) || text[start] == ' ' {
+		return "", 0, 0, false
+	}
+
+	for from := start + 1; from <= len(text)-n; {
+		end := strings.Index(text[from:], delim)
+		if end < 0 {
+			return "", 0, 0, false
+		}
+		end += from
+		after := end + n
+		if text[end-1] != ' ' && (c != '_' || after >= len(text) || !isWordByte(text[after])) {
+			return text[start:end], style, after - i, true
+		}
+		from = end + 1
+	}
+	return "", 0, 0, false
+}
+
+// wrapSpans lays spans out in lines of width columns, starting the first line
+// with prefix first and the others with rest. Words longer than a line
+// overflow it.
+func wrapSpans(spans []span, width int, first, rest string) []string {
+	type word struct {
+		parts []span
+		width int
+	}
+
+	var words []word
+	var current word
+	for _, s := range spans {
+		for j, field := range strings.Split(s.text, " ") {
+			if j > 0 && len(current.parts) > 0 {
+				words = append(words, current)
+				current = word{}
+			}
+			if field != "" {
+				current.parts = append(current.parts, span{text: field, style: s.style, url: s.url})
+				current.width += utf8.RuneCountInString(field)
+			}
+		}
+	}
+	if len(current.parts) > 0 {
+		words = append(words, current)
+	}
+
+	var lines []string
+	var line []span
+	prefix, column := first, visibleWidth(first)
+
+	for _, w := range words {
+		if len(line) > 0 && column+1+w.width > width {
+			lines = append(lines, prefix+formatSpans(line))
+			line = nil
+			prefix, column = rest, visibleWidth(rest)
+		}
+
+		if len(line) > 0 {
+			// The space between two words of a code span or link keeps its style
+			separator := span{text: " "}
+			if previous, next := line[len(line)-1], w.parts[0]; next.style == previous.style && next.url == previous.url {
+				separator = span{text: " ", style: next.style, url: next.url}
+			}
+			line = append(line, separator)
+			column++
+		}
+		line = append(line, w.parts...)
+		column += w.width
+	}
+
+	return append(lines, prefix+formatSpans(line))
+}
+
+// formatSpans joins spans into a line, merging neighbours of the same style
+func formatSpans(spans []span) string {
+	var sb strings.Builder
+	for i := 0; i < len(spans); {
+		s := spans[i]
+		for i++; i < len(spans) && spans[i].style == s.style && spans[i].url == s.url; i++ {
+			s.text += spans[i].text
+		}
+		writeSpan(&sb, s)
+	}
+	return sb.String()
+}
+
+// writeSpan writes s with its style, as an OSC 8 hyperlink when it has a url
+func writeSpan(sb *strings.Builder, s span) {
+	if s.url != "" {
+		fmt.Fprintf(sb, "\033]8;;%s\033\\", s.url)
+	}
+	if s.style != 0 {
+		sb.WriteString(s.style.sgr())
+	}
+	sb.WriteString(s.text)
+	if s.style != 0 {
+		sb.WriteString("\033[0m")
+	}
+	if s.url != "" {
+		sb.WriteString("\033]8;;\033\\")
+	}
+}
+
+// sgr returns the escape sequence selecting the style
+func (s textStyle) sgr() string {
+	var codes []string
+	if s&styleBold != 0 {
+		codes = append(codes, "1")
+	}
+	if s&styleDim != 0 {
+		codes = append(codes, "2")
+	}
+	if s&styleItalic != 0 {
+		codes = append(codes, "3")
+	}
+	if s&(styleUnderline|styleLink) != 0 {
+		codes = append(codes, "4")
+	}
+	if s&styleStrike != 0 {
+		codes = append(codes, "9")
+	}
+	switch {
+	case s&styleCode != 0:
+		codes = append(codes, fmt.Sprintf("38;5;%d;48;5;%d", colorCode, colorCodeBg))
+	case s&styleLink != 0:
+		codes = append(codes, fmt.Sprintf("38;5;%d", colorLink))
+	case s&styleHeading != 0:
+		codes = append(codes, fmt.Sprintf("38;5;%d", colorHeading))
+	}
+	return "\033[" + strings.Join(codes, ";") + "m"
+}
+
+// runLength counts the repetitions of text[i] starting at i
+func runLength(text string, i int) int {
+	n := 1
+	for i+n < len(text) && text[i+n] == text[i] {
+		n++
+	}
+	return n
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= '0' && c <= '9' || c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z'
+}
+
+// visibleWidth counts the columns of s, skipping ANSI color sequences
+func visibleWidth(s string) int {
+	width := 0
+	inEscape := false
+	for _, r := range s {
+		switch {
+		case r == '\033':
+			inEscape = true
+		case inEscape:
+			if r == 'm' {
+				inEscape = false
+			}
+		default:
+			width++
+		}
+	}
+	return width
+}
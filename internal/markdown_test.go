@@ -0,0 +1,238 @@
+package internal
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// styled renders text the way RenderMarkdown writes a span of that style
+func styled(text string, style textStyle) string {
+	return style.sgr() + text + "\033[0m"
+}
+
+func linked(text, url string, style textStyle) string {
+	return "\033]8;;" + url + "\033\\" + styled(text, style) + "\033]8;;\033\\"
+}
+
+var escapeRe = regexp.MustCompile("\033\\[[0-9;]*m|\033]8;;[^\033]*\033\\\\")
+
+// plainText strips colors and hyperlinks from rendered output
+func plainText(s string) string {
+	return escapeRe.ReplaceAllString(s, "")
+}
+
+func TestRenderMarkdownInline(t *testing.T) {
+	tests := []struct {
+		name       string
+		source     string
+		hyperlinks bool
+		want       string
+	}{
+		{
+			name:   "plain",
+			source: "just text",
+			want:   "just text",
+		},
+		{
+			name:   "italic and bold",
+			source: "an *italic* and **bold** word",
+			want:   "an " + styled("italic", styleItalic) + " and " + styled("bold", styleBold) + " word",
+		},
+		{
+			name:   "nested emphasis",
+			source: "**bold *both* bold**",
+			want:   styled("bold", styleBold) + " " + styled("both", styleBold|styleItalic) + " " + styled("bold", styleBold),
+		},
+		{
+			name:   "bold italic",
+			source: "***both***",
+			want:   styled("both", styleBold|styleItalic),
+		},
+		{
+			name:   "underscores",
+			source: "_italic_ and __bold__",
+			want:   styled("italic", styleItalic) + " and " + styled("bold", styleBold),
+		},
+		{
+			name:   "unclosed asterisk",
+			source: "2 * 3 and *open",
+			want:   "2 * 3 and *open",
+		},
+		{
+			name:   "unclosed underscore",
+			source: "_open and __also",
+			want:   "_open and __also",
+		},
+		{
+			name:   "intraword underscores",
+			source: "snake_case_name",
+			want:   "snake_case_name",
+		},
+		{
+			name:   "strikethrough",
+			source: "~~gone~~",
+			want:   styled("gone", styleStrike),
+		},
+		{
+			name:   "code with asterisks",
+			source: "run `a * b * c` now",
+			want:   "run " + styled("a * b * c", styleCode) + " now",
+		},
+		{
+			name:   "code with emphasis markers",
+			source: "`**not bold**`",
+			want:   styled("**not bold**", styleCode),
+		},
+		{
+			name:   "double backtick code",
+			source: "``a ` b``",
+			want:   styled("a ` b", styleCode),
+		},
+		{
+			name:   "escaped asterisks",
+			source: `\*literal\*`,
+			want:   "*literal*",
+		},
+		{
+			name:   "link without hyperlinks",
+			source: "see [the docs](https://example.com/docs)",
+			want:   "see " + styled("the docs", styleLink) + " " + styled("(https://example.com/docs)", styleDim),
+		},
+		{
+			name:       "link with hyperlinks",
+			source:     "see [docs](https://example.com/docs \"title\")",
+			hyperlinks: true,
+			want:       "see " + linked("docs", "https://example.com/docs", styleLink),
+		},
+		{
+			name:   "bare url",
+			source: "at https://example.com.",
+			want:   "at " + styled("https://example.com", styleLink) + ".",
+		},
+		{
+			name:       "autolink",
+			source:     "<https://example.com>",
+			hyperlinks: true,
+			want:       linked("https://example.com", "https://example.com", styleLink),
+		},
+		{
+			name:   "image",
+			source: "![screenshot](https://example.com/a.png)",
+			want:   styled("image: screenshot", styleLink) + " " + styled("(https://example.com/a.png)", styleDim),
+		},
+		{
+			name:   "bracket without link",
+			source: "[not a link] and [x](",
+			want:   "[not a link] and [x](",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := RenderMarkdown(tt.source, 80, tt.hyperlinks)
+			if got != tt.want {
+				t.Errorf("RenderMarkdown(%q)\n got %q\nwant %q", tt.source, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRenderMarkdownBlocks(t *testing.T) {
+	tests := []struct {
+		name   string
+		source string
+		width  int
+		want   []string // lines without colors
+	}{
+		{
+			name:   "task boxes",
+			source: "- [ ] todo\n- [x] done\n- [X] also done",
+			width:  40,
+			want:   []string{"☐ todo", "☑ done", "☑ also done"},
+		},
+		{
+			name:   "nested and ordered lists",
+			source: "- one\n  - two\n1. first\n2) [ ] second",
+			width:  40,
+			want:   []string{"• one", "  ◦ two", "1. first", "2) ☐ second"},
+		},
+		{
+			name:   "list items wrap under their text",
+			source: "- alpha beta gamma delta",
+			width:  12,
+			want:   []string{"• alpha beta", "  gamma", "  delta"},
+		},
+		{
+			name:   "fenced code does not wrap",
+			source: "```go\nfmt.Println(\"a very long line of code that is wider than the terminal\")\n\tx := 1\n```",
+			width:  20,
+			want: []string{
+				" fmt.Println(\"a very long line of code that is wider than the terminal\")",
+				"     x := 1         ",
+			},
+		},
+		{
+			name:   "fence keeps markdown literal",
+			source: "~~~\n# not a heading\n- not a list\n~~~",
+			width:  16,
+			want:   []string{" # not a heading", " - not a list   "},
+		},
+		{
+			name:   "long words overflow instead of being cut",
+			source: "see https://example.com/a/very/long/path/that/does/not/fit here",
+			width:  16,
+			want:   []string{"see", "https://example.com/a/very/long/path/that/does/not/fit", "here"},
+		},
+		{
+			name:   "multibyte text wraps by characters",
+			source: "ünïcödé wörds àré çöüntéd by rünés",
+			width:  14,
+			want:   []string{"ünïcödé wörds", "àré çöüntéd by", "rünés"},
+		},
+		{
+			name:   "headings, quotes and rules",
+			source: "# Title #\n\n> quoted *text*\n\n---",
+			width:  10,
+			want:   []string{"Title", "", "│ quoted", "│ text", "", "──────────"},
+		},
+		{
+			name:   "comments are hidden and blank lines collapse",
+			source: "before\n<!-- hint\nmore -->\n\n\n\nafter\n\n",
+			width:  40,
+			want:   []string{"before", "", "after"},
+		},
+		{
+			name:   "tables are kept",
+			source: "| a | b |\n|---|---|\n| 1 | 2 |",
+			width:  5,
+			want:   []string{"| a | b |", "|---|---|", "| 1 | 2 |"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := strings.Split(plainText(RenderMarkdown(tt.source, tt.width, false)), "\n")
+			if strings.Join(got, "\n") != strings.Join(tt.want, "\n") {
+				t.Errorf("RenderMarkdown(%q, %d)\n got %q\nwant %q", tt.source, tt.width, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVisibleWidth(t *testing.T) {
+	tests := []struct {
+		s    string
+		want int
+	}{
+		{"plain", 5},
+		{styled("bold", styleBold), 4},
+		{"ünï", 3},
+		{"☐ task", 6},
+	}
+	for _, tt := range tests {
+		if got := visibleWidth(tt.s); got != tt.want {
+			t.Errorf("visibleWidth(%q) = %d, want %d", tt.s, got, tt.want)
+		}
+	}
+}
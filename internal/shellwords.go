@@ -0,0 +1,79 @@
+package internal
+
+import (
+	"fmt"
+	"runtime"
+	"strings"
+)
+
+// SplitShellWords splits a command line the way a POSIX shell would, without
+// expanding anything: words are separated by blanks, single quotes keep text
+// literally, double quotes keep blanks and allow \" \\ \$ \` escapes, and a
+// backslash outside quotes escapes the next character. On Windows backslashes
+// separate paths, so they are kept literally apart from \" in double quotes.
+//
+//	code --wait               → [code --wait]
+//	"/opt/My Editor/ed" -n    → [/opt/My Editor/ed -n]
+func SplitShellWords(line string) ([]string, error) {
+	var words []string
+	var word strings.Builder
+	inWord := false
+	escapes := "\"\\$`"
+	if runtime.GOOS == "windows" {
+		escapes = "\""
+	}
+
+	runes := []rune(line)
+	for i := 0; i < len(runes); i++ {
+		r := runes[i]
+		switch {
+		case r == ' ' || r == '\t' || r == '\n':
+			if inWord {
+				words = append(words, word.String())
+				word.Reset()
+				inWord = false
+			}
+		case r == '\\' && runtime.GOOS != "windows":
+			inWord = true
+			if i+1 < len(runes) {
+				i++
+				if runes[i] != '\n' { // backslash-newline continues the line
+					word.WriteRune(runes[i])
+				}
+			}
+		case r == '\'':
+			inWord = true
+			end := strings.IndexRune(string(runes[i+1:]), '\'')
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated single quote in %q", line)
+			}
+			quoted := []rune(string(runes[i+1:])[:end])
+			word.WriteString(string(quoted))
+			i += len(quoted) + 1
+		case r == '"':
+			inWord = true
+			closed := false
+			for i++; i < len(runes); i++ {
+				if runes[i] == '"' {
+					closed = true
+					break
+				}
+				if runes[i] == '\\' && i+1 < len(runes) && strings.ContainsRune(escapes, runes[i+1]) {
+					i++
+				}
+				word.WriteRune(runes[i])
+			}
+			if !closed {
+				return nil, fmt.Errorf("unterminated double quote in %q", line)
+			}
+		default:
+			inWord = true
+			word.WriteRune(r)
+		}
+	}
+
+	if inWord {
+		words = append(words, word.String())
+	}
+	return words, nil
+}
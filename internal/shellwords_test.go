@@ -0,0 +1,75 @@
+package internal
+
+import (
+	"reflect"
+	"runtime"
+	"testing"
+)
+
+func TestSplitShellWords(t *testing.T) {
+	tests := []struct {
+		name string
+		line string
+		want []string
+		err  bool
+	}{
+		{name: "empty", line: "", want: nil},
+		{name: "blanks only", line: " \t\n ", want: nil},
+		{name: "single word", line: "vim", want: []string{"vim"}},
+		{name: "arguments", line: "  code   --wait\t-n ", want: []string{"code", "--wait", "-n"}},
+		{name: "single quotes", line: `'/opt/My Editor/ed' -n`, want: []string{"/opt/My Editor/ed", "-n"}},
+		{name: "single quotes are literal", line: `'a\b "c" $d'`, want: []string{`a\b "c" $d`}},
+		{name: "double quotes", line: `"/opt/My Editor/ed" -n`, want: []string{"/opt/My Editor/ed", "-n"}},
+		{name: "empty quotes make a word", line: `ed "" ''`, want: []string{"ed", "", ""}},
+		{name: "quotes join words", line: `a"b c"'d e'f`, want: []string{"ab cd ef"}},
+		{name: "unterminated single quote", line: `ed 'file`, err: true},
+		{name: "unterminated double quote", line: `ed "file`, err: true},
+		{name: "unterminated after escaped quote", line: `ed "a\"`, err: true},
+	}
+	if runtime.GOOS == "windows" {
+		tests = append(tests, []struct {
+			name string
+			line string
+			want []string
+			err  bool
+		}{
+			{name: "windows path", line: `C:\Windows\notepad.exe`, want: []string{`C:\Windows\notepad.exe`}},
+			{name: "windows quoted path", line: `"C:\Program Files\Notepad++\notepad++.exe" -multiInst`,
+				want: []string{`C:\Program Files\Notepad++\notepad++.exe`, "-multiInst"}},
+			{name: "windows escaped quote", line: `"say \"hi\""`, want: []string{`say "hi"`}},
+			{name: "windows trailing backslash", line: `"C:\dir\\"`, want: []string{`C:\dir\\`}},
+		}...)
+	} else {
+		tests = append(tests, []struct {
+			name string
+			line string
+			want []string
+			err  bool
+		}{
+			{name: "escaped blank", line: `/opt/My\ Editor/ed -n`, want: []string{"/opt/My Editor/ed", "-n"}},
+			{name: "escaped quotes", line: `\"a\' b`, want: []string{`"a'`, "b"}},
+			{name: "escapes in double quotes", line: `"a\"b\\c\$d\` + "`" + `e\n"`, want: []string{`a"b\c$d` + "`" + `e\n`}},
+			{name: "line continuation", line: "ed \\\n-n", want: []string{"ed", "-n"}},
+			{name: "trailing backslash", line: `ed\`, want: []string{"ed"}},
+			{name: "windows path needs quotes", line: `C:\Windows\notepad.exe`, want: []string{"C:Windowsnotepad.exe"}},
+		}...)
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := SplitShellWords(tt.line)
+			if tt.err {
+				if err == nil {
+					t.Fatalf("SplitShellWords(%q) = %q, want an error", tt.line, got)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("SplitShellWords(%q) error = %v", tt.line, err)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SplitShellWords(%q) = %q, want %q", tt.line, got, tt.want)
+			}
+		})
+	}
+}
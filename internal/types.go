@@ -1,13 +1,36 @@
 // Package internal contains shared types and utilities used across gt commands.
 package internal
 
+// Issue states as reported by every backend
+const (
+	StateOpen   = "OPEN"
+	StateClosed = "CLOSED"
+)
+
 type Label struct {
 	Name string `json:"name"`
 }
 
+type User struct {
+	Login string `json:"login"`
+}
+
+type Comment struct {
+	Author    User   `json:"author"`
+	Body      string `json:"body"`
+	CreatedAt string `json:"createdAt"`
+}
+
 type Issue struct {
-	Number    int     `json:"number"`
-	Title     string  `json:"title"`
-	Labels    []Label `json:"labels"`
-	CreatedAt string  `json:"createdAt"`
+	Number    int       `json:"number"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body,omitempty"`
+	State     string    `json:"state,omitempty"`
+	Labels    []Label   `json:"labels"`
+	Assignees []User    `json:"assignees,omitempty"`
+	Comments  []Comment `json:"comments,omitempty"`
+	CreatedAt string    `json:"createdAt"`
+	UpdatedAt string    `json:"updatedAt,omitempty"`
+	ClosedAt  string    `json:"closedAt,omitempty"`
+	URL       string    `json:"url,omitempty"`
 }